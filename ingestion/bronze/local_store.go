@@ -0,0 +1,29 @@
+package bronze
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore is an ObjectStore backed by the local filesystem, rooted at
+// dir.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore builds a LocalStore rooted at dir.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) Put(key string, body []byte) error {
+	path := filepath.Join(s.dir, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create bronze directory: %w", err)
+	}
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		return fmt.Errorf("failed to write bronze object %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package bronze
+
+import "ai-data-lake/ingestion/schema"
+
+// ColumnStats summarizes one column across a partition file's rows, so a
+// future silver-layer compactor can prune files without reading them.
+type ColumnStats struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	NullCount int      `json:"null_count"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+}
+
+// Manifest describes one Parquet partition file: its row count and
+// per-column statistics. Written alongside the file it describes: the
+// manifest for "part-<uuid>.parquet" is "part-<uuid>.manifest.json".
+type Manifest struct {
+	File     string        `json:"file"`
+	RowCount int           `json:"row_count"`
+	Columns  []ColumnStats `json:"columns"`
+}
+
+// buildManifest computes row count and per-column null/min/max stats for
+// rows under fields's schema.
+func buildManifest(fields []schema.Field, rows []map[string]interface{}, file string) Manifest {
+	columns := make([]ColumnStats, 0, len(fields))
+	for _, f := range fields {
+		stats := ColumnStats{Name: f.Name, Type: f.Type}
+		for _, row := range rows {
+			v, ok := row[f.Name]
+			if !ok || v == nil {
+				stats.NullCount++
+				continue
+			}
+			n, isNumeric := toFloat64(v)
+			if !isNumeric {
+				continue
+			}
+			if stats.Min == nil || n < *stats.Min {
+				min := n
+				stats.Min = &min
+			}
+			if stats.Max == nil || n > *stats.Max {
+				max := n
+				stats.Max = &max
+			}
+		}
+		columns = append(columns, stats)
+	}
+
+	return Manifest{File: file, RowCount: len(rows), Columns: columns}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,126 @@
+package bronze
+
+import (
+	"encoding/json"
+	"fmt"
+
+	parquetsource "github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/writer"
+
+	"ai-data-lake/ingestion/schema"
+)
+
+// parquetType maps a detected schema.Field.Type to a xitongsys/parquet-go
+// column tag, mirroring schema's avroType so bronze Parquet files and the
+// registry's Avro/JSON-Schema documents agree on the same simplified type
+// system: int64, double, bool, timestamp-millis, string.
+func parquetType(fieldType string) string {
+	switch fieldType {
+	case "integer":
+		return "type=INT64"
+	case "float":
+		return "type=DOUBLE"
+	case "boolean":
+		return "type=BOOLEAN"
+	case "datetime", "date", "timestamp":
+		return "type=INT64, convertedtype=TIMESTAMP_MILLIS"
+	default:
+		// email, url, uuid, phone, json, string, array[*], object all fall
+		// back to a plain string column.
+		return "type=BYTE_ARRAY, convertedtype=UTF8"
+	}
+}
+
+// parquetFieldName replaces "." (used for nested field paths) with "_"
+// since Parquet column names must be valid identifiers, mirroring
+// schema.avroFieldName.
+func parquetFieldName(name string) string {
+	out := make([]rune, 0, len(name))
+	for _, r := range name {
+		if r == '.' {
+			out = append(out, '_')
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+type parquetSchemaDoc struct {
+	Tag    string          `json:"Tag"`
+	Fields []parquetColumn `json:"Fields"`
+}
+
+type parquetColumn struct {
+	Tag string `json:"Tag"`
+}
+
+// buildParquetSchemaJSON renders fields as a xitongsys/parquet-go JSON
+// schema document. Every column is OPTIONAL unless marked Required,
+// matching schema.BuildAvroSchema's nullability rule.
+func buildParquetSchemaJSON(fields []schema.Field) (string, error) {
+	columns := make([]parquetColumn, 0, len(fields))
+	for _, f := range fields {
+		repetition := "OPTIONAL"
+		if f.Required {
+			repetition = "REQUIRED"
+		}
+		columns = append(columns, parquetColumn{
+			Tag: fmt.Sprintf("name=%s, %s, repetitiontype=%s", parquetFieldName(f.Name), parquetType(f.Type), repetition),
+		})
+	}
+
+	doc := parquetSchemaDoc{
+		Tag:    "name=bronze_record, repetitiontype=REQUIRED",
+		Fields: columns,
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode parquet schema: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// normalizeRow re-keys row by parquetFieldName and drops anything not in
+// fields, so it lines up with the schema handed to the JSON writer.
+func normalizeRow(fields []schema.Field, row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if v, ok := row[f.Name]; ok {
+			out[parquetFieldName(f.Name)] = v
+		}
+	}
+	return out
+}
+
+// encodeParquet renders rows under fields's schema as a Parquet file and
+// returns its bytes.
+func encodeParquet(fields []schema.Field, rows []map[string]interface{}) ([]byte, error) {
+	schemaJSON, err := buildParquetSchemaJSON(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := parquetsource.NewBufferFile()
+	pw, err := writer.NewJSONWriter(schemaJSON, pf, 4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+
+	for _, row := range rows {
+		rowJSON, err := json.Marshal(normalizeRow(fields, row))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode row: %w", err)
+		}
+		if err := pw.Write(string(rowJSON)); err != nil {
+			return nil, fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	if err := pw.WriteStop(); err != nil {
+		return nil, fmt.Errorf("failed to finalize parquet file: %w", err)
+	}
+
+	return pf.Bytes(), nil
+}
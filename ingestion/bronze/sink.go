@@ -0,0 +1,231 @@
+package bronze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ai-data-lake/ingestion/schema"
+)
+
+// Config controls when a partition's current file is rolled and flushed.
+type Config struct {
+	// MaxBytes rolls the current file once its buffered (JSON-encoded) size
+	// reaches this many bytes. Zero disables the size threshold.
+	MaxBytes int64
+	// MaxAge rolls the current file once it's been open at least this long,
+	// even if it hasn't hit MaxBytes, so low-volume partitions still flush.
+	// Zero disables the age threshold. Enforced both lazily (on the next
+	// Write to that partition) and, if Run is started, by a periodic sweep -
+	// a partition that receives one row and then nothing else still needs
+	// Run to ever see daylight.
+	MaxAge time.Duration
+	// SweepInterval controls how often Run checks every partition's age
+	// against MaxAge. Defaults to 30 seconds if zero.
+	SweepInterval time.Duration
+}
+
+// partitionKey identifies one Hive-style partition: tenant, schema hash, and
+// ingest date. Rendered as "tenant=<id>/schema=<hash>/ingest_date=<date>".
+type partitionKey struct {
+	TenantID   string
+	SchemaHash string
+	IngestDate string
+}
+
+func (k partitionKey) dir() string {
+	return path.Join(
+		fmt.Sprintf("tenant=%s", k.TenantID),
+		fmt.Sprintf("schema=%s", k.SchemaHash),
+		fmt.Sprintf("ingest_date=%s", k.IngestDate),
+	)
+}
+
+// partitionBuffer accumulates rows for one partition between rolls.
+type partitionBuffer struct {
+	fields    []schema.Field
+	rows      []map[string]interface{}
+	openedAt  time.Time
+	sizeBytes int64
+}
+
+// Sink is the Parquet bronze writer: it buffers rows per Hive-style
+// partition (tenant/schema/ingest_date) and flushes each to an ObjectStore
+// as a Parquet file plus a JSON manifest once Config's size or age
+// threshold is hit.
+type Sink struct {
+	store ObjectStore
+	cfg   Config
+
+	mu     sync.Mutex
+	active map[partitionKey]*partitionBuffer
+}
+
+// NewSink builds a Sink writing Parquet partitions to store.
+func NewSink(store ObjectStore, cfg Config) *Sink {
+	return &Sink{
+		store:  store,
+		cfg:    cfg,
+		active: make(map[partitionKey]*partitionBuffer),
+	}
+}
+
+// Write appends data (under its detected fields) to tenantID/schemaHash's
+// current partition buffer for today's ingest date, then rolls and flushes
+// that partition, data included, if it has now hit the configured size or
+// age threshold.
+func (s *Sink) Write(tenantID, schemaHash string, fields []schema.Field, data map[string]interface{}) error {
+	key := partitionKey{
+		TenantID:   tenantID,
+		SchemaHash: schemaHash,
+		IngestDate: time.Now().UTC().Format("2006-01-02"),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf, ok := s.active[key]
+	if !ok {
+		buf = &partitionBuffer{fields: fields, openedAt: time.Now()}
+		s.active[key] = buf
+	} else {
+		buf.fields = unionFields(buf.fields, fields)
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode row: %w", err)
+	}
+	buf.rows = append(buf.rows, data)
+	buf.sizeBytes += int64(len(encoded))
+
+	if !s.shouldRoll(buf) {
+		return nil
+	}
+	delete(s.active, key)
+	return s.flush(key, buf)
+}
+
+// unionFields merges incoming into existing, appending any field not
+// already present by name. Schema evolution can add fields to a partition
+// after its buffer is already open, so a partition's field set must grow
+// to cover every row written into it, not just the first.
+func unionFields(existing, incoming []schema.Field) []schema.Field {
+	seen := make(map[string]bool, len(existing))
+	for _, f := range existing {
+		seen[f.Name] = true
+	}
+	out := existing
+	for _, f := range incoming {
+		if seen[f.Name] {
+			continue
+		}
+		seen[f.Name] = true
+		out = append(out, f)
+	}
+	return out
+}
+
+func (s *Sink) shouldRoll(buf *partitionBuffer) bool {
+	if s.cfg.MaxBytes > 0 && buf.sizeBytes >= s.cfg.MaxBytes {
+		return true
+	}
+	if s.cfg.MaxAge > 0 && time.Since(buf.openedAt) >= s.cfg.MaxAge {
+		return true
+	}
+	return false
+}
+
+// Run periodically flushes every partition that has aged past Config.MaxAge,
+// independent of whether it ever receives another Write, until ctx is
+// canceled. Without Run, a partition that receives a single row and then
+// goes quiet never rolls on its own, since shouldRoll is otherwise only
+// checked from inside Write. A zero MaxAge disables the sweep entirely, same
+// as the lazy check in Write.
+func (s *Sink) Run(ctx context.Context) {
+	if s.cfg.MaxAge <= 0 {
+		return
+	}
+	interval := s.cfg.SweepInterval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.flushAged(); err != nil {
+				log.Printf("bronze: periodic age-based flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// flushAged flushes every partition that's been open at least MaxAge,
+// regardless of its buffered size.
+func (s *Sink) flushAged() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, buf := range s.active {
+		if time.Since(buf.openedAt) < s.cfg.MaxAge {
+			continue
+		}
+		if err := s.flush(key, buf); err != nil {
+			return err
+		}
+		delete(s.active, key)
+	}
+	return nil
+}
+
+// Flush rolls every partition with buffered rows regardless of threshold.
+// Call on shutdown so the last, not-yet-full file for each partition isn't
+// lost.
+func (s *Sink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, buf := range s.active {
+		if len(buf.rows) == 0 {
+			continue
+		}
+		if err := s.flush(key, buf); err != nil {
+			return err
+		}
+		delete(s.active, key)
+	}
+	return nil
+}
+
+func (s *Sink) flush(key partitionKey, buf *partitionBuffer) error {
+	partID := uuid.NewString()
+	parquetKey := path.Join(key.dir(), fmt.Sprintf("part-%s.parquet", partID))
+	manifestKey := path.Join(key.dir(), fmt.Sprintf("part-%s.manifest.json", partID))
+
+	parquetBytes, err := encodeParquet(buf.fields, buf.rows)
+	if err != nil {
+		return fmt.Errorf("failed to encode parquet partition %s: %w", parquetKey, err)
+	}
+	if err := s.store.Put(parquetKey, parquetBytes); err != nil {
+		return err
+	}
+
+	manifest := buildManifest(buf.fields, buf.rows, path.Base(parquetKey))
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest for %s: %w", parquetKey, err)
+	}
+	return s.store.Put(manifestKey, manifestBytes)
+}
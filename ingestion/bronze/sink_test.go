@@ -0,0 +1,127 @@
+package bronze
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"ai-data-lake/ingestion/schema"
+)
+
+// memStore is a minimal in-memory ObjectStore for exercising Sink without
+// touching the filesystem. It's safe for concurrent use since Run flushes
+// from its own goroutine.
+type memStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{objects: make(map[string][]byte)}
+}
+
+func (m *memStore) Put(key string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memStore) len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.objects)
+}
+
+func (m *memStore) manifest(t *testing.T) Manifest {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, body := range m.objects {
+		if len(key) > len(".manifest.json") && key[len(key)-len(".manifest.json"):] == ".manifest.json" {
+			var man Manifest
+			if err := json.Unmarshal(body, &man); err != nil {
+				t.Fatalf("failed to decode manifest %s: %v", key, err)
+			}
+			return man
+		}
+	}
+	t.Fatal("no manifest written")
+	return Manifest{}
+}
+
+// TestSinkWriteUnionsFieldsAcrossEvolution verifies that a field added via
+// schema evolution after a partition buffer is already open still shows up
+// in the flushed Parquet row data and manifest stats, instead of being
+// silently dropped.
+func TestSinkWriteUnionsFieldsAcrossEvolution(t *testing.T) {
+	store := newMemStore()
+	sink := NewSink(store, Config{})
+
+	v1 := []schema.Field{{Name: "id", Type: "integer"}}
+	if err := sink.Write("tenant-a", "hash1", v1, map[string]interface{}{"id": float64(1)}); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+
+	v2 := []schema.Field{{Name: "id", Type: "integer"}, {Name: "email", Type: "email"}}
+	if err := sink.Write("tenant-a", "hash1", v2, map[string]interface{}{"id": float64(2), "email": "a@b.com"}); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	man := store.manifest(t)
+	if man.RowCount != 2 {
+		t.Fatalf("row count = %d, want 2", man.RowCount)
+	}
+
+	var emailCol *ColumnStats
+	for i := range man.Columns {
+		if man.Columns[i].Name == "email" {
+			emailCol = &man.Columns[i]
+		}
+	}
+	if emailCol == nil {
+		t.Fatal("manifest missing column added after buffer was opened")
+	}
+	if emailCol.NullCount != 1 {
+		t.Errorf("email null_count = %d, want 1 (only the first row predates the field)", emailCol.NullCount)
+	}
+}
+
+// TestSinkRunFlushesAgedPartitionWithoutFurtherWrites verifies a low-volume
+// partition - one Write and then silence - still gets flushed by Run's
+// periodic sweep, instead of sitting unflushed forever because shouldRoll is
+// otherwise only checked from inside Write.
+func TestSinkRunFlushesAgedPartitionWithoutFurtherWrites(t *testing.T) {
+	store := newMemStore()
+	sink := NewSink(store, Config{MaxAge: 10 * time.Millisecond, SweepInterval: 5 * time.Millisecond})
+
+	if err := sink.Write("tenant-a", "hash1", []schema.Field{{Name: "id", Type: "integer"}}, map[string]interface{}{"id": float64(1)}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		sink.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(200 * time.Millisecond)
+	for store.len() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("partition was never flushed by Run's periodic sweep")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,10 @@
+package bronze
+
+// ObjectStore is the write side of the bronze sink's storage backend: a
+// partition's Parquet file and manifest are each a single Put. Implementations:
+// LocalStore (local filesystem, for dev) and S3Store (S3/MinIO-compatible).
+type ObjectStore interface {
+	// Put writes body under key, creating any parent "directories" implied
+	// by key's path segments.
+	Put(key string, body []byte) error
+}
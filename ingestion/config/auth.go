@@ -0,0 +1,30 @@
+package config
+
+import (
+	"log"
+	"time"
+)
+
+// AuthConfig controls TenantAuth: the JWKS it validates bearer tokens
+// against and the issuer/audience it requires.
+type AuthConfig struct {
+	Enabled bool `env:"AUTH_ENABLED" default:"false"`
+
+	// JWKSURL is fetched on startup and re-fetched every JWKSRefreshInterval
+	// so a signing key rotation doesn't require a restart.
+	JWKSURL             string        `env:"AUTH_JWKS_URL" default:""`
+	JWKSRefreshInterval time.Duration `env:"AUTH_JWKS_REFRESH_INTERVAL" default:"1h"`
+
+	Issuer   string `env:"AUTH_ISSUER" default:""`
+	Audience string `env:"AUTH_AUDIENCE" default:""`
+}
+
+// LoadAuthConfig reads TenantAuth settings from the environment via
+// LoadConfig, exiting the process if any value is invalid.
+func LoadAuthConfig() *AuthConfig {
+	cfg, err := LoadConfig[AuthConfig]()
+	if err != nil {
+		log.Fatalf("failed to load auth config: %v", err)
+	}
+	return &cfg
+}
@@ -0,0 +1,32 @@
+package config
+
+import "log"
+
+// BronzeConfig controls the bronze Parquet sink: which ObjectStore backend
+// it writes to and when it rolls a partition's current file.
+type BronzeConfig struct {
+	// Backend selects the ObjectStore: "local" (default, filesystem) or
+	// "s3" (S3/MinIO-compatible).
+	Backend  string `env:"BRONZE_STORE_BACKEND" default:"local"`
+	LocalDir string `env:"BRONZE_LOCAL_DIR" default:"bronze"`
+
+	RollMaxBytes      int `env:"BRONZE_ROLL_MAX_BYTES" default:"134217728" min:"1"` // 128MB
+	RollMaxAgeSeconds int `env:"BRONZE_ROLL_MAX_AGE_SECONDS" default:"300" min:"1"`
+
+	S3Bucket          string `env:"BRONZE_S3_BUCKET" default:""`
+	S3Region          string `env:"BRONZE_S3_REGION" default:"us-east-1"`
+	S3Endpoint        string `env:"BRONZE_S3_ENDPOINT" default:""` // set for MinIO/non-AWS endpoints
+	S3AccessKeyID     string `env:"BRONZE_S3_ACCESS_KEY_ID" default:""`
+	S3SecretAccessKey string `env:"BRONZE_S3_SECRET_ACCESS_KEY" default:""`
+	S3ForcePathStyle  bool   `env:"BRONZE_S3_FORCE_PATH_STYLE" default:"false"`
+}
+
+// LoadBronzeConfig reads bronze sink settings from the environment via
+// LoadConfig, exiting the process if any value is invalid.
+func LoadBronzeConfig() *BronzeConfig {
+	cfg, err := LoadConfig[BronzeConfig]()
+	if err != nil {
+		log.Fatalf("failed to load bronze config: %v", err)
+	}
+	return &cfg
+}
@@ -1,48 +1,60 @@
 package config
 
 import (
+	"log"
 	"os"
 	"strings"
 )
 
 type HTTPConfig struct {
-	Port         string
+	Port         string `env:"HTTP_PORT" default:"8080"`
 	CORS         CORSConfig
 	RateLimiting RateLimitConfig
+	Gzip         GzipConfig
 }
 
 type CORSConfig struct {
-	AllowedOrigins   []string
-	AllowedMethods   []string
-	AllowedHeaders   []string
-	ExposedHeaders   []string
-	AllowCredentials bool
-	MaxAge           int
+	AllowedOrigins   []string `env:"CORS_ALLOWED_ORIGINS" default:"*"`
+	AllowedMethods   []string `env:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,DELETE,OPTIONS"`
+	AllowedHeaders   []string `env:"CORS_ALLOWED_HEADERS" default:"Content-Type,Authorization,X-Requested-With"`
+	ExposedHeaders   []string `env:"CORS_EXPOSED_HEADERS" default:"X-Total-Count"`
+	AllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" default:"false"`
+	MaxAge           int      `env:"CORS_MAX_AGE" default:"86400" min:"0"` // 24 hours
 }
 
 type RateLimitConfig struct {
-	Enabled        bool
-	RequestsPerMin int
-	BurstSize      int
+	Enabled        bool `env:"RATE_LIMITING_ENABLED" default:"false"`
+	RequestsPerMin int  `env:"RATE_LIMIT_REQUESTS_PER_MIN" default:"60" min:"1" max:"100000"`
+	BurstSize      int  `env:"RATE_LIMIT_BURST_SIZE" default:"10" min:"1" max:"100000"`
+
+	// TenantOverrides optionally sets distinct limits for specific tenants
+	// as "tenant:requests_per_min:burst_size,tenant:requests_per_min:burst_size",
+	// overriding RequestsPerMin/BurstSize for the listed tenants. Parsed by
+	// middleware.ParseTenantLimitOverrides.
+	TenantOverrides string `env:"RATE_LIMIT_TENANT_OVERRIDES" default:""`
+
+	// Backend selects where limiter state lives: "memory" (default, one
+	// bucket per process) or "redis" (shared across replicas).
+	Backend       string `env:"RATE_LIMIT_BACKEND" default:"memory"`
+	RedisAddr     string `env:"RATE_LIMIT_REDIS_ADDR" default:"localhost:6379"`
+	RedisPassword string `env:"RATE_LIMIT_REDIS_PASSWORD" default:""`
+	RedisDB       int    `env:"RATE_LIMIT_REDIS_DB" default:"0" min:"0"`
 }
 
+type GzipConfig struct {
+	Enabled          bool `env:"HTTP_GZIP_ENABLED" default:"true"`
+	MinSizeBytes     int  `env:"HTTP_GZIP_MIN_SIZE_BYTES" default:"1024" min:"0"`
+	CompressionLevel int  `env:"HTTP_GZIP_COMPRESSION_LEVEL" default:"5" min:"1" max:"9"`
+}
+
+// LoadHTTPConfig reads HTTP, CORS, rate-limit, and gzip settings from the
+// environment via LoadConfig, exiting the process if any value is invalid.
 func LoadHTTPConfig() *HTTPConfig {
-	return &HTTPConfig{
-		Port: getEnv("HTTP_PORT", "8080"),
-		CORS: CORSConfig{
-			AllowedOrigins:   parseStringSlice(getEnv("CORS_ALLOWED_ORIGINS", "*")),
-			AllowedMethods:   parseStringSlice(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS")),
-			AllowedHeaders:   parseStringSlice(getEnv("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-Requested-With")),
-			ExposedHeaders:   parseStringSlice(getEnv("CORS_EXPOSED_HEADERS", "X-Total-Count")),
-			AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
-			MaxAge:           getEnvInt("CORS_MAX_AGE", 86400), // 24 hours
-		},
-		RateLimiting: RateLimitConfig{
-			Enabled:        getEnv("RATE_LIMITING_ENABLED", "false") == "true",
-			RequestsPerMin: getEnvInt("RATE_LIMIT_REQUESTS_PER_MIN", 60),
-			BurstSize:      getEnvInt("RATE_LIMIT_BURST_SIZE", 10),
-		},
+	cfg, err := LoadConfig[HTTPConfig]()
+	if err != nil {
+		log.Fatalf("failed to load HTTP config: %v", err)
 	}
+	return &cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -52,18 +64,6 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		// Simple int parsing - in production you'd want proper error handling
-		if value == "0" {
-			return 0
-		}
-		// For simplicity, return default if not a simple number
-		return defaultValue
-	}
-	return defaultValue
-}
-
 func parseStringSlice(value string) []string {
 	if value == "" {
 		return []string{}
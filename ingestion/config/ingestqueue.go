@@ -0,0 +1,22 @@
+package config
+
+import "log"
+
+// IngestQueueConfig controls the bounded ingestion worker pool: how many
+// requests run concurrently across all tenants, and how deep one tenant's
+// backlog is allowed to grow before new requests are rejected.
+type IngestQueueConfig struct {
+	Workers            int `env:"INGEST_QUEUE_WORKERS" default:"32" min:"1" max:"10000"`
+	PerTenantQueueSize int `env:"INGEST_QUEUE_PER_TENANT_SIZE" default:"100" min:"1" max:"100000"`
+	RetryAfterSeconds  int `env:"INGEST_QUEUE_RETRY_AFTER_SECONDS" default:"5" min:"1" max:"3600"`
+}
+
+// LoadIngestQueueConfig reads ingestion worker pool settings from the
+// environment via LoadConfig, exiting the process if any value is invalid.
+func LoadIngestQueueConfig() *IngestQueueConfig {
+	cfg, err := LoadConfig[IngestQueueConfig]()
+	if err != nil {
+		log.Fatalf("failed to load ingest queue config: %v", err)
+	}
+	return &cfg
+}
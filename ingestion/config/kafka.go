@@ -0,0 +1,59 @@
+package config
+
+// KafkaConfig holds settings for the Kafka producer shared across the
+// ingestion service.
+type KafkaConfig struct {
+	Brokers     []string
+	Compression string
+	// ApplicationGzip gzips the JSON body before it reaches kafka-go and sets
+	// Content-Encoding: gzip on the message headers. Useful when brokers
+	// don't have the Compression codec enabled.
+	ApplicationGzip bool
+	Security        KafkaSecurityConfig
+}
+
+// KafkaSecurityConfig holds SASL/TLS/OAuth2 settings for connecting to
+// secured Kafka clusters.
+type KafkaSecurityConfig struct {
+	SASLMechanism string // "none", "plain", "scram-sha-256", "scram-sha-512", "oauth"
+	SASLUsername  string
+	SASLPassword  string
+
+	TLSEnabled  bool
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthTokenURL     string
+	OAuthScopes       []string
+}
+
+// LoadKafkaConfig reads Kafka producer settings from the environment.
+// KAFKA_PRODUCER_COMPRESSION (and the legacy HTTP_INGEST_COMPRESSION alias)
+// accept "none", "gzip", "snappy", "lz4", or "zstd".
+func LoadKafkaConfig() *KafkaConfig {
+	compression := getEnv("KAFKA_PRODUCER_COMPRESSION", getEnv("HTTP_INGEST_COMPRESSION", "none"))
+
+	return &KafkaConfig{
+		Brokers:         parseStringSlice(getEnv("KAFKA_BROKERS", "kafka:29092")),
+		Compression:     compression,
+		ApplicationGzip: getEnv("KAFKA_PRODUCER_APPLICATION_GZIP", "false") == "true",
+		Security: KafkaSecurityConfig{
+			SASLMechanism: getEnv("KAFKA_SASL_MECHANISM", "none"),
+			SASLUsername:  getEnv("KAFKA_SASL_USERNAME", ""),
+			SASLPassword:  getEnv("KAFKA_SASL_PASSWORD", ""),
+
+			TLSEnabled:  getEnv("KAFKA_TLS_ENABLED", "false") == "true",
+			TLSCertFile: getEnv("KAFKA_TLS_CERT_FILE", ""),
+			TLSKeyFile:  getEnv("KAFKA_TLS_KEY_FILE", ""),
+			TLSCAFile:   getEnv("KAFKA_TLS_CA_FILE", ""),
+
+			OAuthClientID:     getEnv("KAFKA_OAUTH_CLIENT_ID", ""),
+			OAuthClientSecret: getEnv("KAFKA_OAUTH_CLIENT_SECRET", ""),
+			OAuthTokenURL:     getEnv("KAFKA_OAUTH_TOKEN_URL", ""),
+			OAuthScopes:       parseStringSlice(getEnv("KAFKA_OAUTH_SCOPES", "")),
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadError aggregates every invalid value found while loading a config
+// struct, so operators see all of their mistakes in one pass instead of
+// fixing env vars one failed startup at a time.
+type LoadError struct {
+	Errors []string
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  - %s", strings.Join(e.Errors, "\n  - "))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadConfig populates a struct of type T from environment variables
+// described by its field tags:
+//
+//	env:"NAME"       the environment variable to read
+//	default:"value"  used when the variable is unset or empty
+//	min:"n" max:"n"  inclusive bounds enforced for numeric fields
+//
+// Supported field types: string, bool, int, time.Duration (via
+// ParseDuration), []string (comma-separated), and nested structs (walked
+// recursively, no prefix applied - each leaf field carries its own full env
+// name). Every invalid value is collected and returned together as a single
+// *LoadError rather than failing on the first one.
+func LoadConfig[T any]() (T, error) {
+	var cfg T
+	rv := reflect.ValueOf(&cfg).Elem()
+
+	var errs []string
+	loadStruct(rv, &errs)
+
+	if len(errs) > 0 {
+		return cfg, &LoadError{Errors: errs}
+	}
+	return cfg, nil
+}
+
+func loadStruct(rv reflect.Value, errs *[]string) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			loadStruct(value, errs)
+			continue
+		}
+
+		envName, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		raw := getEnv(envName, field.Tag.Get("default"))
+		if err := setField(value, field, raw); err != nil {
+			*errs = append(*errs, fmt.Sprintf("%s (env %s=%q): %v", field.Name, envName, raw, err))
+		}
+	}
+}
+
+func setField(value reflect.Value, field reflect.StructField, raw string) error {
+	switch {
+	case field.Type == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("not a valid duration: %w", err)
+		}
+		value.Set(reflect.ValueOf(d))
+		return nil
+
+	case field.Type.Kind() == reflect.String:
+		value.SetString(raw)
+		return nil
+
+	case field.Type.Kind() == reflect.Bool:
+		value.SetBool(raw == "true")
+		return nil
+
+	case field.Type.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("not a valid integer: %w", err)
+		}
+		if err := checkBounds(n, field.Tag); err != nil {
+			return err
+		}
+		value.SetInt(int64(n))
+		return nil
+
+	case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.String:
+		value.Set(reflect.ValueOf(parseStringSlice(raw)))
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type)
+	}
+}
+
+func checkBounds(n int, tag reflect.StructTag) error {
+	if minStr, ok := tag.Lookup("min"); ok {
+		min, err := strconv.Atoi(minStr)
+		if err == nil && n < min {
+			return fmt.Errorf("must be >= %d", min)
+		}
+	}
+	if maxStr, ok := tag.Lookup("max"); ok {
+		max, err := strconv.Atoi(maxStr)
+		if err == nil && n > max {
+			return fmt.Errorf("must be <= %d", max)
+		}
+	}
+	return nil
+}
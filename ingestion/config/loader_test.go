@@ -0,0 +1,114 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+type testLeafConfig struct {
+	Name     string        `env:"TEST_LOADER_NAME" default:"anon"`
+	Count    int           `env:"TEST_LOADER_COUNT" default:"1" min:"1" max:"10"`
+	Enabled  bool          `env:"TEST_LOADER_ENABLED" default:"false"`
+	Timeout  time.Duration `env:"TEST_LOADER_TIMEOUT" default:"5s"`
+	Tags     []string      `env:"TEST_LOADER_TAGS" default:"a,b"`
+	Internal string
+}
+
+type testNestedConfig struct {
+	Leaf testLeafConfig
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	cfg, err := LoadConfig[testLeafConfig]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Name != "anon" || cfg.Count != 1 || cfg.Enabled || cfg.Timeout != 5*time.Second {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Errorf("unexpected default tags: %v", cfg.Tags)
+	}
+	if cfg.Internal != "" {
+		t.Errorf("untagged field should be left zero-valued, got %q", cfg.Internal)
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("TEST_LOADER_NAME", "custom")
+	t.Setenv("TEST_LOADER_COUNT", "7")
+	t.Setenv("TEST_LOADER_ENABLED", "true")
+	t.Setenv("TEST_LOADER_TIMEOUT", "10s")
+	t.Setenv("TEST_LOADER_TAGS", "x,y,z")
+
+	cfg, err := LoadConfig[testLeafConfig]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Name != "custom" || cfg.Count != 7 || !cfg.Enabled || cfg.Timeout != 10*time.Second {
+		t.Errorf("unexpected values: %+v", cfg)
+	}
+	if len(cfg.Tags) != 3 || cfg.Tags[2] != "z" {
+		t.Errorf("unexpected tags: %v", cfg.Tags)
+	}
+}
+
+func TestLoadConfigNestedStruct(t *testing.T) {
+	t.Setenv("TEST_LOADER_COUNT", "3")
+
+	cfg, err := LoadConfig[testNestedConfig]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Leaf.Count != 3 {
+		t.Errorf("expected nested field to be populated, got %+v", cfg.Leaf)
+	}
+}
+
+func TestLoadConfigAggregatesErrors(t *testing.T) {
+	t.Setenv("TEST_LOADER_COUNT", "not-a-number")
+	t.Setenv("TEST_LOADER_TIMEOUT", "not-a-duration")
+
+	_, err := LoadConfig[testLeafConfig]()
+	if err == nil {
+		t.Fatal("expected an error for invalid values")
+	}
+
+	loadErr, ok := err.(*LoadError)
+	if !ok {
+		t.Fatalf("expected *LoadError, got %T", err)
+	}
+	if len(loadErr.Errors) != 2 {
+		t.Errorf("expected both invalid fields to be reported, got %d: %v", len(loadErr.Errors), loadErr.Errors)
+	}
+}
+
+func TestLoadConfigEnforcesBounds(t *testing.T) {
+	t.Setenv("TEST_LOADER_COUNT", "100")
+
+	_, err := LoadConfig[testLeafConfig]()
+	if err == nil {
+		t.Fatal("expected an error for out-of-range value")
+	}
+}
+
+func TestLoadHTTPConfigDefaults(t *testing.T) {
+	cfg := LoadHTTPConfig()
+	if cfg.Port != "8080" {
+		t.Errorf("expected default port 8080, got %s", cfg.Port)
+	}
+	if cfg.RateLimiting.RequestsPerMin != 60 {
+		t.Errorf("expected default RequestsPerMin 60, got %d", cfg.RateLimiting.RequestsPerMin)
+	}
+	if !cfg.Gzip.Enabled {
+		t.Errorf("expected gzip enabled by default")
+	}
+}
+
+func TestLoadHTTPConfigHonorsRateLimitEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_REQUESTS_PER_MIN", "120")
+	cfg := LoadHTTPConfig()
+	if cfg.RateLimiting.RequestsPerMin != 120 {
+		t.Errorf("expected RATE_LIMIT_REQUESTS_PER_MIN to take effect, got %d", cfg.RateLimiting.RequestsPerMin)
+	}
+}
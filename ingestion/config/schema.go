@@ -0,0 +1,21 @@
+package config
+
+import "log"
+
+// SchemaConfig holds the schema compatibility policy: a default mode plus a
+// raw per-tenant overrides string, both handed to
+// schema.ParsePolicyConfig.
+type SchemaConfig struct {
+	CompatibilityMode          string `env:"SCHEMA_COMPATIBILITY_MODE" default:"none"`
+	CompatibilityModeOverrides string `env:"SCHEMA_COMPATIBILITY_MODE_OVERRIDES" default:""`
+}
+
+// LoadSchemaConfig reads schema compatibility settings from the environment
+// via LoadConfig, exiting the process if any value is invalid.
+func LoadSchemaConfig() *SchemaConfig {
+	cfg, err := LoadConfig[SchemaConfig]()
+	if err != nil {
+		log.Fatalf("failed to load schema config: %v", err)
+	}
+	return &cfg
+}
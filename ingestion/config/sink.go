@@ -0,0 +1,34 @@
+package config
+
+import "log"
+
+// SinkConfig controls the bronze-drain Sink: which tenants' confirmed
+// schema topics it consumes, and how hard it retries a failing drain
+// before routing the record to its DLQ.
+type SinkConfig struct {
+	// Tenants lists the tenant IDs the Sink maintains a consumer group for.
+	// A tenant's group only subscribes to its confirmed schema topics;
+	// newly confirmed schemas are picked up on the next poll, no restart
+	// required.
+	Tenants []string `env:"SINK_TENANTS" default:""`
+
+	// PollIntervalSeconds controls how often the Sink re-checks each
+	// tenant's confirmed schemas for newly confirmed topics to drain.
+	PollIntervalSeconds int `env:"SINK_POLL_INTERVAL_SECONDS" default:"30" min:"1"`
+
+	// MaxRetries bounds how many times the Sink retries a failing drain,
+	// with exponential backoff from RetryBaseWaitMillis, before routing the
+	// record to its schema-<hash>-dlq topic.
+	MaxRetries          int `env:"SINK_MAX_RETRIES" default:"5" min:"0" max:"20"`
+	RetryBaseWaitMillis int `env:"SINK_RETRY_BASE_WAIT_MILLIS" default:"500" min:"1"`
+}
+
+// LoadSinkConfig reads bronze-drain Sink settings from the environment via
+// LoadConfig, exiting the process if any value is invalid.
+func LoadSinkConfig() *SinkConfig {
+	cfg, err := LoadConfig[SinkConfig]()
+	if err != nil {
+		log.Fatalf("failed to load sink config: %v", err)
+	}
+	return &cfg
+}
@@ -0,0 +1,25 @@
+package config
+
+import "log"
+
+// TracingConfig controls OpenTelemetry trace export for the ingestion
+// service. When disabled, no tracer provider is installed and otel's global
+// no-op provider stays in effect, so instrumentation calls are no-ops.
+type TracingConfig struct {
+	Enabled     bool   `env:"TRACING_ENABLED" default:"false"`
+	ServiceName string `env:"TRACING_SERVICE_NAME" default:"ingestion"`
+
+	// OTLPEndpoint is the OTLP/HTTP collector endpoint ("host:port", no
+	// scheme) spans are exported to.
+	OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT" default:"localhost:4318"`
+}
+
+// LoadTracingConfig reads tracing settings from the environment via
+// LoadConfig, exiting the process if any value is invalid.
+func LoadTracingConfig() *TracingConfig {
+	cfg, err := LoadConfig[TracingConfig]()
+	if err != nil {
+		log.Fatalf("failed to load tracing config: %v", err)
+	}
+	return &cfg
+}
@@ -0,0 +1,211 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Admin performs Kafka cluster administration beyond simple topic creation:
+// listing/describing/altering topic configs and reassigning partitions
+// (KIP-455), so operators can reshape schema-hash topics as the cluster
+// grows without downtime.
+type Admin struct {
+	brokers  []string
+	security SecurityConfig
+}
+
+// NewAdmin creates an Admin that talks to brokers using security.
+func NewAdmin(brokers []string, security SecurityConfig) *Admin {
+	return &Admin{brokers: brokers, security: security}
+}
+
+func (a *Admin) client() (*kafka.Client, error) {
+	transport, err := a.security.transport()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure Kafka security: %w", err)
+	}
+	return &kafka.Client{
+		Addr:      kafka.TCP(a.brokers...),
+		Timeout:   10 * time.Second,
+		Transport: transport,
+	}, nil
+}
+
+// TopicSummary describes a single schema-<hash> topic.
+type TopicSummary struct {
+	Topic          string
+	SchemaHash     string
+	PartitionCount int
+	RetentionMS    string
+}
+
+// ListSchemaTopics returns every topic matching the schema-* naming
+// convention, along with its partition count and retention.ms.
+func (a *Admin) ListSchemaTopics() ([]TopicSummary, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := client.Metadata(context.Background(), &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+
+	var summaries []TopicSummary
+	for _, topic := range metadata.Topics {
+		if !strings.HasPrefix(topic.Name, "schema-") {
+			continue
+		}
+		schemaHash := strings.TrimPrefix(topic.Name, "schema-")
+
+		retention := ""
+		if entries, err := a.DescribeTopicConfig(schemaHash); err == nil {
+			retention = entries["retention.ms"]
+		}
+
+		summaries = append(summaries, TopicSummary{
+			Topic:          topic.Name,
+			SchemaHash:     schemaHash,
+			PartitionCount: len(topic.Partitions),
+			RetentionMS:    retention,
+		})
+	}
+
+	return summaries, nil
+}
+
+// DescribeTopicConfig returns the current config entries (e.g.
+// retention.ms, cleanup.policy) for the topic backing schemaHash.
+func (a *Admin) DescribeTopicConfig(schemaHash string) (map[string]string, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	topic := fmt.Sprintf("schema-%s", schemaHash)
+
+	resp, err := client.DescribeConfigs(context.Background(), &kafka.DescribeConfigsRequest{
+		Resources: []kafka.DescribeConfigRequestResource{
+			{ResourceType: kafka.ResourceTypeTopic, ResourceName: topic},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe config for %s: %w", topic, err)
+	}
+
+	entries := make(map[string]string)
+	for _, resource := range resp.Resources {
+		for _, entry := range resource.ConfigEntries {
+			entries[entry.ConfigName] = entry.ConfigValue
+		}
+	}
+	return entries, nil
+}
+
+// AlterTopicConfig updates config entries (e.g. retention.ms,
+// cleanup.policy) on the topic backing schemaHash without recreating it.
+func (a *Admin) AlterTopicConfig(schemaHash string, entries map[string]string) error {
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	topic := fmt.Sprintf("schema-%s", schemaHash)
+
+	_, err = client.AlterConfigs(context.Background(), &kafka.AlterConfigsRequest{
+		Resources: []kafka.AlterConfigRequestResource{
+			{
+				ResourceType: kafka.ResourceTypeTopic,
+				ResourceName: topic,
+				Configs:      alterConfigsFrom(entries),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to alter config for %s: %w", topic, err)
+	}
+	return nil
+}
+
+// alterConfigsFrom converts a plain config-name/value map into the
+// AlterConfigRequestConfig slice kafka.Client.AlterConfigs expects.
+func alterConfigsFrom(entries map[string]string) []kafka.AlterConfigRequestConfig {
+	var configs []kafka.AlterConfigRequestConfig
+	for name, value := range entries {
+		configs = append(configs, kafka.AlterConfigRequestConfig{Name: name, Value: value})
+	}
+	return configs
+}
+
+// PartitionAssignment describes the desired broker replicas for one
+// partition of a schema-hash topic.
+type PartitionAssignment struct {
+	Partition int
+	BrokerIDs []int
+}
+
+// AlterPartitionReassignments triggers a KIP-455 reassignment of the given
+// partitions' replicas for the topic backing schemaHash, so operators can
+// rebalance across brokers without downtime.
+func (a *Admin) AlterPartitionReassignments(schemaHash string, assignments []PartitionAssignment) error {
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	topic := fmt.Sprintf("schema-%s", schemaHash)
+
+	var partitions []kafka.AlterPartitionReassignmentsRequestAssignment
+	for _, a := range assignments {
+		partitions = append(partitions, kafka.AlterPartitionReassignmentsRequestAssignment{
+			PartitionID: a.Partition,
+			BrokerIDs:   a.BrokerIDs,
+		})
+	}
+
+	_, err = client.AlterPartitionReassignments(context.Background(), &kafka.AlterPartitionReassignmentsRequest{
+		Topic:       topic,
+		Assignments: partitions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to alter partition reassignments for %s: %w", topic, err)
+	}
+	return nil
+}
+
+// ListPartitionReassignments returns the in-progress reassignments for the
+// topic backing schemaHash, if any.
+func (a *Admin) ListPartitionReassignments(schemaHash string) ([]PartitionAssignment, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	topic := fmt.Sprintf("schema-%s", schemaHash)
+
+	resp, err := client.ListPartitionReassignments(context.Background(), &kafka.ListPartitionReassignmentsRequest{
+		Topics: map[string]kafka.ListPartitionReassignmentsRequestTopic{topic: {}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partition reassignments for %s: %w", topic, err)
+	}
+
+	return partitionAssignmentsFrom(resp.Topics), nil
+}
+
+// partitionAssignmentsFrom flattens a ListPartitionReassignments response's
+// per-topic partitions into the PartitionAssignment slice this package's
+// callers work with.
+func partitionAssignmentsFrom(topics map[string]kafka.ListPartitionReassignmentsResponseTopic) []PartitionAssignment {
+	var assignments []PartitionAssignment
+	for _, result := range topics {
+		for _, p := range result.Partitions {
+			assignments = append(assignments, PartitionAssignment{
+				Partition: p.PartitionIndex,
+				BrokerIDs: p.AddingReplicas,
+			})
+		}
+	}
+	return assignments
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// TestAlterConfigsFrom verifies AlterTopicConfig's entries map is converted
+// into AlterConfigRequestConfig values using that struct's actual Name/Value
+// fields.
+func TestAlterConfigsFrom(t *testing.T) {
+	configs := alterConfigsFrom(map[string]string{"retention.ms": "604800000"})
+
+	if len(configs) != 1 {
+		t.Fatalf("len(configs) = %d, want 1", len(configs))
+	}
+	if configs[0].Name != "retention.ms" || configs[0].Value != "604800000" {
+		t.Errorf("configs[0] = %+v, want {Name: retention.ms, Value: 604800000}", configs[0])
+	}
+}
+
+// TestPartitionAssignmentsFrom verifies ListPartitionReassignments flattens
+// a response's per-topic Partitions slice, reading PartitionIndex (not
+// PartitionID, which this response type doesn't have).
+func TestPartitionAssignmentsFrom(t *testing.T) {
+	topics := map[string]kafka.ListPartitionReassignmentsResponseTopic{
+		"schema-abc123": {
+			Partitions: []kafka.ListPartitionReassignmentsResponsePartition{
+				{PartitionIndex: 0, AddingReplicas: []int{1, 2}},
+				{PartitionIndex: 1, AddingReplicas: []int{3}},
+			},
+		},
+	}
+
+	assignments := partitionAssignmentsFrom(topics)
+
+	if len(assignments) != 2 {
+		t.Fatalf("len(assignments) = %d, want 2", len(assignments))
+	}
+
+	byPartition := make(map[int][]int, len(assignments))
+	for _, a := range assignments {
+		byPartition[a.Partition] = a.BrokerIDs
+	}
+	if got := byPartition[0]; len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("partition 0 BrokerIDs = %v, want [1 2]", got)
+	}
+	if got := byPartition[1]; len(got) != 1 || got[0] != 3 {
+		t.Errorf("partition 1 BrokerIDs = %v, want [3]", got)
+	}
+}
@@ -0,0 +1,230 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-data-lake/ingestion/tracing"
+)
+
+// RebalanceStrategy selects how partitions are divided among group members.
+type RebalanceStrategy string
+
+const (
+	RebalanceRange      RebalanceStrategy = "range"
+	RebalanceRoundRobin RebalanceStrategy = "roundrobin"
+	RebalanceSticky     RebalanceStrategy = "sticky"
+)
+
+// StartOffset controls where a consumer group begins reading on first join.
+type StartOffset string
+
+const (
+	StartOffsetEarliest StartOffset = "earliest"
+	StartOffsetLatest   StartOffset = "latest"
+)
+
+// ConsumerGroupConfig configures a ConsumerGroup.
+type ConsumerGroupConfig struct {
+	Brokers           []string
+	GroupID           string
+	StartOffset       StartOffset
+	SessionTimeout    time.Duration
+	HeartbeatInterval time.Duration
+	RebalanceStrategy RebalanceStrategy
+	// Serializer strips the active schema registry's wire format (e.g.
+	// ConfluentRegistry's magic-byte + schema-ID header) from each
+	// message's value before it's JSON-decoded. Nil reads plain JSON,
+	// matching a Producer configured the same way.
+	Serializer Serializer
+}
+
+// Handler processes a single decoded record. Returning an error leaves the
+// message uncommitted so it will be redelivered.
+type Handler func(ctx context.Context, msg kafka.Message, record RecordMessage) error
+
+// ConsumerGroup consumes one or more schema-<hash> topics (or a topic regex)
+// as part of a Kafka consumer group, delivering RecordMessage values to a
+// Handler with at-least-once semantics. Offsets advance when Consume commits
+// a message whose handler returned nil, or when the caller calls
+// MarkMessage, so failed handlers can be retried by restarting the group at
+// the same offset.
+type ConsumerGroup struct {
+	cfg    ConsumerGroupConfig
+	reader *kafka.Reader
+}
+
+// NewConsumerGroup creates a ConsumerGroup that joins GroupID and subscribes
+// to the given topics once Consume is called.
+func NewConsumerGroup(cfg ConsumerGroupConfig) *ConsumerGroup {
+	if cfg.SessionTimeout == 0 {
+		cfg.SessionTimeout = 30 * time.Second
+	}
+	if cfg.HeartbeatInterval == 0 {
+		cfg.HeartbeatInterval = 3 * time.Second
+	}
+	if cfg.RebalanceStrategy == "" {
+		cfg.RebalanceStrategy = RebalanceRange
+	}
+	return &ConsumerGroup{cfg: cfg}
+}
+
+// resolveTopics expands a list of topic names and/or regex patterns (e.g.
+// "schema-.*") against the topics currently known to the brokers.
+func resolveTopics(brokers []string, topicsOrPatterns []string) ([]string, error) {
+	conn, err := kafka.Dial("tcp", brokers[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions: %w", err)
+	}
+
+	known := make(map[string]bool, len(partitions))
+	for _, p := range partitions {
+		known[p.Topic] = true
+	}
+
+	seen := make(map[string]bool)
+	var resolved []string
+	for _, pattern := range topicsOrPatterns {
+		if known[pattern] {
+			if !seen[pattern] {
+				seen[pattern] = true
+				resolved = append(resolved, pattern)
+			}
+			continue
+		}
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic pattern %q: %w", pattern, err)
+		}
+		for topic := range known {
+			if re.MatchString(topic) && !seen[topic] {
+				seen[topic] = true
+				resolved = append(resolved, topic)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+func groupBalancers(strategy RebalanceStrategy) []kafka.GroupBalancer {
+	switch strategy {
+	case RebalanceRoundRobin, RebalanceSticky:
+		// kafka-go has no dedicated sticky balancer; round-robin gives the
+		// closest approximation of minimizing partition churn on rejoin.
+		return []kafka.GroupBalancer{&kafka.RoundRobinGroupBalancer{}}
+	default:
+		return []kafka.GroupBalancer{&kafka.RangeGroupBalancer{}}
+	}
+}
+
+// Consume joins the consumer group, subscribes to topics (names and/or regex
+// patterns like "schema-.*"), and delivers messages to handler until ctx is
+// canceled or an unrecoverable error occurs. It survives rebalances because
+// the underlying kafka.Reader rejoins the group automatically. Consume
+// commits each message's offset itself once handler returns nil for it.
+func (g *ConsumerGroup) Consume(ctx context.Context, topicsOrPatterns []string, handler Handler) error {
+	topics, err := resolveTopics(g.cfg.Brokers, topicsOrPatterns)
+	if err != nil {
+		return err
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("no topics matched %v", topicsOrPatterns)
+	}
+
+	startOffset := kafka.LastOffset
+	if g.cfg.StartOffset == StartOffsetEarliest {
+		startOffset = kafka.FirstOffset
+	}
+
+	g.reader = kafka.NewReader(kafka.ReaderConfig{
+		Brokers:           g.cfg.Brokers,
+		GroupID:           g.cfg.GroupID,
+		GroupTopics:       topics,
+		StartOffset:       startOffset,
+		SessionTimeout:    g.cfg.SessionTimeout,
+		HeartbeatInterval: g.cfg.HeartbeatInterval,
+		GroupBalancers:    groupBalancers(g.cfg.RebalanceStrategy),
+	})
+	defer g.reader.Close()
+
+	for {
+		msg, err := g.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		jsonValue := msg.Value
+		if g.cfg.Serializer != nil {
+			decoded, err := g.cfg.Serializer.Decode(jsonValue)
+			if err != nil {
+				log.Printf("Skipping message with invalid wire format on %s[%d]@%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+				continue
+			}
+			jsonValue = decoded
+		}
+
+		var record RecordMessage
+		if err := json.Unmarshal(jsonValue, &record); err != nil {
+			log.Printf("Skipping unparsable message on %s[%d]@%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+			continue
+		}
+
+		msgCtx := otel.GetTextMapPropagator().Extract(ctx, headerCarrier{headers: &msg.Headers})
+		msgCtx, span := tracing.Tracer.Start(msgCtx, "kafka.consume", trace.WithAttributes(
+			attribute.String("messaging.destination", msg.Topic),
+		))
+
+		if err := handler(msgCtx, msg, record); err != nil {
+			log.Printf("Handler error for tenant %s on %s[%d]@%d, leaving uncommitted: %v",
+				record.TenantID, msg.Topic, msg.Partition, msg.Offset, err)
+			span.End()
+			continue
+		}
+		span.End()
+
+		if err := g.reader.CommitMessages(ctx, msg); err != nil {
+			log.Printf("Failed to commit offset for %s[%d]@%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+		}
+	}
+}
+
+// MarkMessage commits the offset for msg, signaling that it (and everything
+// before it on its partition) has been durably processed. Use this instead
+// of relying solely on Consume's auto-commit when a handler defers
+// acknowledgement until after a downstream side effect (e.g. a bronze write)
+// has succeeded.
+func (g *ConsumerGroup) MarkMessage(ctx context.Context, msg kafka.Message) error {
+	if g.reader == nil {
+		return fmt.Errorf("consumer group is not running")
+	}
+	if err := g.reader.CommitMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+	return nil
+}
+
+// Close stops the consumer group and releases its connection to the brokers.
+func (g *ConsumerGroup) Close() error {
+	if g.reader == nil {
+		return nil
+	}
+	return g.reader.Close()
+}
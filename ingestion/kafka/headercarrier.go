@@ -0,0 +1,31 @@
+package kafka
+
+import "github.com/segmentio/kafka-go"
+
+// headerCarrier adapts a []kafka.Header slice to otel's
+// propagation.TextMapCarrier, so trace context can be injected into (on
+// produce) and extracted from (on consume) Kafka message headers.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
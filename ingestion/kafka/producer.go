@@ -1,17 +1,69 @@
 package kafka
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-data-lake/ingestion/metrics"
+	"ai-data-lake/ingestion/tracing"
 )
 
+// ProducerConfig configures the Producer's Kafka writer.
+type ProducerConfig struct {
+	// Compression sets kafka.Writer.Compression: "none", "gzip", "snappy",
+	// "lz4", or "zstd". Requires the brokers to have the codec enabled.
+	Compression string
+	// ApplicationGzip gzips the JSON body before writing it and sets
+	// Content-Encoding: gzip on the message headers, instead of relying on
+	// the broker-side codec.
+	ApplicationGzip bool
+	// Security configures SASL/TLS/OAuth2 authentication to the brokers. The
+	// zero value dials brokers with plain TCP, as before.
+	Security SecurityConfig
+	// Serializer wraps each message's JSON body in the active schema
+	// registry's wire format (e.g. ConfluentRegistry's magic-byte +
+	// schema-ID header) before it's written. Nil writes plain JSON.
+	Serializer Serializer
+}
+
+func (c ProducerConfig) compressionCodec() kafka.Compression {
+	switch c.Compression {
+	case "gzip":
+		return kafka.Gzip
+	case "snappy":
+		return kafka.Snappy
+	case "lz4":
+		return kafka.Lz4
+	case "zstd":
+		return kafka.Zstd
+	default:
+		return 0
+	}
+}
+
 type Producer struct {
 	brokers []string
+	cfg     ProducerConfig
+
+	// writerOnce builds writer (and the security transport it wraps, which
+	// mints and caches the SecurityConfig's SASL mechanism) once and reuses
+	// it across every SendRecord/SendToDLQ call, instead of re-dialing and,
+	// for SASLOAuthBearer, re-minting a token on every message.
+	writerOnce sync.Once
+	writer     *kafka.Writer
+	writerErr  error
 }
 
 type RecordMessage struct {
@@ -22,14 +74,31 @@ type RecordMessage struct {
 	ReceivedAt time.Time              `json:"received_at"`
 }
 
-func NewProducer(brokers []string) *Producer {
+// NewProducer creates a Producer that writes to brokers using cfg.
+func NewProducer(brokers []string, cfg ProducerConfig) *Producer {
 	return &Producer{
 		brokers: brokers,
+		cfg:     cfg,
+	}
+}
+
+func gzipBytes(raw []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(raw); err != nil {
+		return nil, err
 	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-// SendRecord sends a data record to Kafka using schema hash as topic
-func (p *Producer) SendRecord(tenantID, schemaHash string, data map[string]interface{}) error {
+// SendRecord sends a data record to Kafka using schema hash as topic. ctx
+// bounds how long the write waits on a slow or unreachable broker; a
+// canceled ctx (client disconnect, server shutdown) aborts the write
+// instead of blocking the caller's goroutine indefinitely.
+func (p *Producer) SendRecord(ctx context.Context, tenantID, schemaHash string, data map[string]interface{}) error {
 	// Use schema hash as topic name to isolate data by schema
 	topic := fmt.Sprintf("schema-%s", schemaHash)
 
@@ -46,37 +115,155 @@ func (p *Producer) SendRecord(tenantID, schemaHash string, data map[string]inter
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	writer := &kafka.Writer{
-		Addr:         kafka.TCP(p.brokers...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
-		RequiredAcks: kafka.RequireOne,
-		Async:        false, // Synchronous for reliability
+	if err := p.write(ctx, topic, schemaHash, []byte(tenantID), messageBytes); err != nil {
+		return err
 	}
-	defer writer.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	log.Printf("Successfully sent record to Kafka topic: %s", topic)
+	return nil
+}
 
-	err = writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(tenantID), // Use tenant ID as key for partitioning
-		Value: messageBytes,
-		Time:  time.Now(),
-	})
+// DLQMessage wraps a RecordMessage that exhausted its retry budget when a
+// consumer tried to process it, so anything reading the DLQ topic can see
+// why the record ended up there alongside the original payload.
+type DLQMessage struct {
+	RecordMessage
+	FailureReason string    `json:"failure_reason"`
+	FailedAt      time.Time `json:"failed_at"`
+}
+
+// SendToDLQ publishes record to its schema-<hash>-dlq topic, wrapped with
+// reason, so an operator replaying the DLQ can see why the record failed.
+func (p *Producer) SendToDLQ(ctx context.Context, hash string, record RecordMessage, reason string) error {
+	topic := fmt.Sprintf("schema-%s-dlq", hash)
+
+	message := DLQMessage{
+		RecordMessage: record,
+		FailureReason: reason,
+		FailedAt:      time.Now(),
+	}
 
+	messageBytes, err := json.Marshal(message)
 	if err != nil {
-		return fmt.Errorf("failed to write message to Kafka: %w", err)
+		return fmt.Errorf("failed to marshal DLQ message: %w", err)
 	}
 
-	log.Printf("Successfully sent record to Kafka topic: %s", topic)
+	if err := p.write(ctx, topic, record.SchemaHash, []byte(record.TenantID), messageBytes); err != nil {
+		return err
+	}
+
+	log.Printf("Routed record to DLQ topic: %s", topic)
 	return nil
 }
 
+// write serializes value per cfg.Serializer (if configured) for schemaHash,
+// gzips it per ApplicationGzip, then synchronously writes it to topic keyed
+// by key, shared by SendRecord and SendToDLQ. The write is bounded to 10
+// seconds from ctx, whichever is shorter. It starts a span (child of
+// whatever span ctx carries) and injects the resulting trace context into
+// the message headers, so a consumer reading the message can continue the
+// same trace.
+func (p *Producer) write(ctx context.Context, topic, schemaHash string, key, value []byte) error {
+	ctx, span := tracing.Tracer.Start(ctx, "kafka.produce", trace.WithAttributes(
+		attribute.String("messaging.destination", topic),
+	))
+	defer span.End()
+
+	start := time.Now()
+	defer func() {
+		metrics.KafkaProduceSeconds.Observe(time.Since(start).Seconds())
+	}()
+
+	if p.cfg.Serializer != nil {
+		encoded, err := p.cfg.Serializer.Encode(ctx, schemaHash, value)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("failed to encode message for schema %s: %w", schemaHash, err)
+		}
+		value = encoded
+	}
+
+	var headers []kafka.Header
+	if p.cfg.ApplicationGzip {
+		gzipped, err := gzipBytes(value)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return fmt.Errorf("failed to gzip message: %w", err)
+		}
+		value = gzipped
+		headers = append(headers, kafka.Header{Key: "Content-Encoding", Value: []byte("gzip")})
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{headers: &headers})
+
+	writer, err := p.getWriter()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := writer.WriteMessages(ctx, kafka.Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   value,
+		Time:    time.Now(),
+		Headers: headers,
+	}); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("failed to write message to Kafka: %w", err)
+	}
+	return nil
+}
+
+// getWriter lazily builds the shared kafka.Writer (and the security
+// transport it wraps) on the first call and returns the same instance on
+// every later call, so the SecurityConfig's SASL mechanism - in particular
+// the OAuth2 TokenSource, which caches and refreshes its own token - is
+// built once per Producer rather than once per message. The Writer has no
+// default Topic; each WriteMessages call sets kafka.Message.Topic instead,
+// so one Writer serves every schema-<hash> topic this Producer publishes
+// to.
+func (p *Producer) getWriter() (*kafka.Writer, error) {
+	p.writerOnce.Do(func() {
+		transport, err := p.cfg.Security.transport()
+		if err != nil {
+			p.writerErr = fmt.Errorf("failed to configure Kafka security: %w", err)
+			return
+		}
+		p.writer = &kafka.Writer{
+			Addr:         kafka.TCP(p.brokers...),
+			Balancer:     &kafka.LeastBytes{},
+			RequiredAcks: kafka.RequireOne,
+			Async:        false, // Synchronous for reliability
+			Compression:  p.cfg.compressionCodec(),
+			Transport:    transport,
+		}
+	})
+	return p.writer, p.writerErr
+}
+
+// Close releases the Producer's shared Kafka writer and its connections.
+// Call once during graceful shutdown.
+func (p *Producer) Close() error {
+	if p.writer == nil {
+		return nil
+	}
+	return p.writer.Close()
+}
+
 // CreateTopic creates a Kafka topic if it doesn't exist
 func (p *Producer) CreateTopic(schemaHash string) error {
 	topic := fmt.Sprintf("schema-%s", schemaHash)
 
-	conn, err := kafka.Dial("tcp", p.brokers[0])
+	dialer, err := p.cfg.Security.dialer()
+	if err != nil {
+		return fmt.Errorf("failed to configure Kafka security: %w", err)
+	}
+
+	conn, err := dialer.Dial("tcp", p.brokers[0])
 	if err != nil {
 		return fmt.Errorf("failed to connect to Kafka: %w", err)
 	}
@@ -87,7 +274,7 @@ func (p *Producer) CreateTopic(schemaHash string) error {
 		return fmt.Errorf("failed to get controller: %w", err)
 	}
 
-	controllerConn, err := kafka.Dial("tcp", fmt.Sprintf("%s:%d", controller.Host, controller.Port))
+	controllerConn, err := dialer.Dial("tcp", fmt.Sprintf("%s:%d", controller.Host, controller.Port))
 	if err != nil {
 		return fmt.Errorf("failed to connect to controller: %w", err)
 	}
@@ -118,7 +305,12 @@ func (p *Producer) CreateTopic(schemaHash string) error {
 func (p *Producer) GetTopicRecordCount(schemaHash string) (int64, error) {
 	topic := fmt.Sprintf("schema-%s", schemaHash)
 
-	conn, err := kafka.Dial("tcp", p.brokers[0])
+	dialer, err := p.cfg.Security.dialer()
+	if err != nil {
+		return 0, fmt.Errorf("failed to configure Kafka security: %w", err)
+	}
+
+	conn, err := dialer.Dial("tcp", p.brokers[0])
 	if err != nil {
 		return 0, fmt.Errorf("failed to connect to Kafka: %w", err)
 	}
@@ -134,7 +326,7 @@ func (p *Producer) GetTopicRecordCount(schemaHash string) (int64, error) {
 
 	// Count records in each partition
 	for _, partition := range partitions {
-		partitionConn, err := kafka.DialLeader(context.Background(), "tcp", p.brokers[0], topic, partition.ID)
+		partitionConn, err := dialer.DialLeader(context.Background(), "tcp", p.brokers[0], topic, partition.ID)
 		if err != nil {
 			log.Printf("Failed to connect to partition %d: %v", partition.ID, err)
 			continue
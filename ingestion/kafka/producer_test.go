@@ -0,0 +1,24 @@
+package kafka
+
+import "testing"
+
+// TestProducerGetWriterIsCachedAcrossCalls verifies the Producer builds its
+// kafka.Writer (and the SecurityConfig transport/SASL mechanism it wraps,
+// e.g. the OAuth2 TokenSource) exactly once, instead of re-minting it on
+// every SendRecord/SendToDLQ call.
+func TestProducerGetWriterIsCachedAcrossCalls(t *testing.T) {
+	p := NewProducer([]string{"localhost:9092"}, ProducerConfig{})
+
+	first, err := p.getWriter()
+	if err != nil {
+		t.Fatalf("getWriter: %v", err)
+	}
+	second, err := p.getWriter()
+	if err != nil {
+		t.Fatalf("getWriter: %v", err)
+	}
+
+	if first != second {
+		t.Fatal("getWriter returned a different *kafka.Writer on the second call, want the same cached instance")
+	}
+}
@@ -0,0 +1,20 @@
+package kafka
+
+import "context"
+
+// Serializer lets a Producer and ConsumerGroup agree on a wire format other
+// than raw JSON for a schema-<hash> topic's payload, so the producer can
+// serialize records in the active schema registry's chosen format (e.g.
+// ConfluentRegistry's magic-byte + schema-ID header) instead of always
+// writing raw JSON. A nil Serializer on ProducerConfig/ConsumerGroupConfig
+// means "write/read plain JSON", which covers every registry backend
+// except Confluent's.
+type Serializer interface {
+	// Encode wraps jsonBytes - the producer's already-JSON-marshaled
+	// RecordMessage/DLQMessage - in this serializer's wire format for the
+	// topic's schema hash.
+	Encode(ctx context.Context, schemaHash string, jsonBytes []byte) ([]byte, error)
+	// Decode strips this serializer's wire format from wireBytes, so the
+	// remaining bytes are the plain JSON body Consume can json.Unmarshal.
+	Decode(wireBytes []byte) (jsonBytes []byte, err error)
+}
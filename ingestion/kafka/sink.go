@@ -0,0 +1,224 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DrainFunc persists one drained record to its final destination (the
+// bronze writer, in practice). Returning an error leaves the message
+// uncommitted so the Sink's retry/backoff loop runs again.
+type DrainFunc func(tenantID, schemaHash string, data map[string]interface{}) error
+
+// ConfirmedTopicsFunc returns the schema hashes currently confirmed for
+// tenantID, so the Sink knows which schema-<hash> topics are safe to drain.
+// ctx bounds the registry lookup it makes.
+type ConfirmedTopicsFunc func(ctx context.Context, tenantID string) ([]string, error)
+
+// SinkConfig configures a Sink.
+type SinkConfig struct {
+	Brokers  []string
+	Security SecurityConfig
+
+	// Tenants lists the tenant IDs the Sink maintains a consumer group for.
+	Tenants []string
+
+	// PollInterval controls how often the Sink re-checks Confirmed for
+	// newly confirmed topics per tenant.
+	PollInterval time.Duration
+
+	// MaxRetries bounds how many times Drain is retried, with exponential
+	// backoff starting at RetryBaseWait, before a record is routed to its
+	// schema-<hash>-dlq topic.
+	MaxRetries    int
+	RetryBaseWait time.Duration
+
+	// Serializer matches the active schema registry's wire format: it's
+	// used both to decode drained messages and to re-encode anything
+	// routed to the DLQ. Nil reads/writes plain JSON.
+	Serializer Serializer
+}
+
+// tenantConsumer tracks the running consumer group for one tenant, so Sink
+// can tell whether its confirmed topic set has changed.
+type tenantConsumer struct {
+	cancel context.CancelFunc
+	topics map[string]bool
+}
+
+// Sink maintains one consumer group per tenant, draining every schema-<hash>
+// topic confirmed (via ConfirmedTopicsFunc) for that tenant into Drain with
+// at-least-once semantics. A record that keeps failing Drain is retried with
+// exponential backoff up to MaxRetries, then routed to its
+// schema-<hash>-dlq topic instead of blocking the partition forever.
+//
+// Confirmed is polled on PollInterval, so a tenant's consumer group is
+// restarted with the newly confirmed topic as soon as it appears - no
+// process restart required.
+type Sink struct {
+	cfg       SinkConfig
+	confirmed ConfirmedTopicsFunc
+	drain     DrainFunc
+	producer  *Producer
+
+	mu     sync.Mutex
+	groups map[string]*tenantConsumer
+}
+
+// NewSink builds a Sink that drains confirmed topics into drain, looking up
+// each tenant's confirmed schema hashes via confirmed.
+func NewSink(cfg SinkConfig, confirmed ConfirmedTopicsFunc, drain DrainFunc) *Sink {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+	if cfg.RetryBaseWait == 0 {
+		cfg.RetryBaseWait = 500 * time.Millisecond
+	}
+	return &Sink{
+		cfg:       cfg,
+		confirmed: confirmed,
+		drain:     drain,
+		producer:  NewProducer(cfg.Brokers, ProducerConfig{Security: cfg.Security, Serializer: cfg.Serializer}),
+		groups:    make(map[string]*tenantConsumer),
+	}
+}
+
+// Run reconciles every tenant's consumer group against its confirmed
+// topics immediately, then again every PollInterval, until ctx is canceled.
+func (s *Sink) Run(ctx context.Context) {
+	s.reconcile(ctx)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.stopAll()
+			return
+		case <-ticker.C:
+			s.reconcile(ctx)
+		}
+	}
+}
+
+// reconcile re-reads each configured tenant's confirmed schemas and starts,
+// restarts, or stops that tenant's consumer group as needed.
+func (s *Sink) reconcile(ctx context.Context) {
+	for _, tenantID := range s.cfg.Tenants {
+		hashes, err := s.confirmed(ctx, tenantID)
+		if err != nil {
+			log.Printf("Sink: failed to list confirmed schemas for tenant %s: %v", tenantID, err)
+			continue
+		}
+		s.ensureConsumer(ctx, tenantID, hashes)
+	}
+}
+
+// ensureConsumer starts a consumer group for tenantID's confirmed topics if
+// none is running, restarts it if the confirmed set changed since the last
+// reconcile, or stops it if tenantID no longer has any confirmed schema.
+func (s *Sink) ensureConsumer(ctx context.Context, tenantID string, hashes []string) {
+	topics := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		topics[fmt.Sprintf("schema-%s", hash)] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, running := s.groups[tenantID]; running {
+		if sameTopicSet(existing.topics, topics) {
+			return
+		}
+		existing.cancel()
+		delete(s.groups, tenantID)
+	}
+	if len(topics) == 0 {
+		return
+	}
+
+	groupCtx, cancel := context.WithCancel(ctx)
+	s.groups[tenantID] = &tenantConsumer{cancel: cancel, topics: topics}
+
+	topicList := make([]string, 0, len(topics))
+	for topic := range topics {
+		topicList = append(topicList, topic)
+	}
+
+	group := NewConsumerGroup(ConsumerGroupConfig{
+		Brokers:     s.cfg.Brokers,
+		GroupID:     fmt.Sprintf("bronze-drain-%s", tenantID),
+		StartOffset: StartOffsetEarliest,
+		Serializer:  s.cfg.Serializer,
+	})
+
+	go func() {
+		if err := group.Consume(groupCtx, topicList, s.handle); err != nil {
+			log.Printf("Sink: consumer group for tenant %s stopped: %v", tenantID, err)
+		}
+	}()
+}
+
+func sameTopicSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for topic := range a {
+		if !b[topic] {
+			return false
+		}
+	}
+	return true
+}
+
+// handle drains one record, retrying with exponential backoff up to
+// MaxRetries before routing it to its DLQ topic. It always returns nil once
+// the record has either been drained or handed to the DLQ, so the caller
+// (ConsumerGroup.Consume) commits the offset and moves on rather than
+// redelivering it forever.
+func (s *Sink) handle(ctx context.Context, msg kafka.Message, record RecordMessage) error {
+	wait := s.cfg.RetryBaseWait
+	var lastErr error
+
+	for attempt := 0; attempt <= s.cfg.MaxRetries; attempt++ {
+		if lastErr = s.drain(record.TenantID, record.SchemaHash, record.Data); lastErr == nil {
+			return nil
+		}
+		if attempt == s.cfg.MaxRetries {
+			break
+		}
+		log.Printf("Sink: drain attempt %d/%d failed for tenant %s, schema %s: %v",
+			attempt+1, s.cfg.MaxRetries, record.TenantID, record.SchemaHash, lastErr)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+
+	log.Printf("Sink: exhausted retries for tenant %s, schema %s, routing to DLQ: %v",
+		record.TenantID, record.SchemaHash, lastErr)
+	if err := s.producer.SendToDLQ(ctx, record.SchemaHash, record, lastErr.Error()); err != nil {
+		return fmt.Errorf("drain failed (%v) and DLQ publish failed: %w", lastErr, err)
+	}
+	return nil
+}
+
+// stopAll cancels every running consumer group, called when Run's context
+// is canceled.
+func (s *Sink) stopAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for tenantID, g := range s.groups {
+		g.cancel()
+		delete(s.groups, tenantID)
+	}
+}
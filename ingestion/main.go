@@ -1,81 +1,160 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/csv"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
-	"sort"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
+	"ai-data-lake/ingestion/bronze"
 	"ai-data-lake/ingestion/config"
 	"ai-data-lake/ingestion/kafka"
+	"ai-data-lake/ingestion/metrics"
 	"ai-data-lake/ingestion/middleware"
 	"ai-data-lake/ingestion/platform"
+	"ai-data-lake/ingestion/schema"
+	"ai-data-lake/ingestion/tracing"
 
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
+const (
+	// wsPongWait is how long a WebSocket connection may go without a pong
+	// before it's considered dead and the read loop gives up on it.
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod keeps pings comfortably inside wsPongWait so a live
+	// connection never times out waiting on its own keepalive.
+	wsPingPeriod = (wsPongWait * 9) / 10
+	// wsWriteWait bounds how long a ping or close frame write may block.
+	wsWriteWait = 10 * time.Second
+)
+
+// wsDeadlines enforces per-message read deadlines on a WebSocket
+// connection, refreshing the deadline on every pong so a silently dead
+// peer is detected within wsPongWait instead of blocking the read loop
+// forever.
+type wsDeadlines struct {
+	conn *websocket.Conn
+}
+
+// newWSDeadlines arms conn's initial read deadline and installs a pong
+// handler that refreshes it.
+func newWSDeadlines(conn *websocket.Conn) *wsDeadlines {
+	d := &wsDeadlines{conn: conn}
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	return d
+}
+
+// ping writes a ping frame with a bounded write deadline.
+func (d *wsDeadlines) ping() error {
+	return d.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+}
+
+// keepalive sends a ping every wsPingPeriod until ctx is canceled or a ping
+// fails, at which point it closes conn so the blocked ReadMessage in the
+// handler's goroutine returns and the connection is cleaned up.
+func (d *wsDeadlines) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.ping(); err != nil {
+				d.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// appCtx is canceled when the process receives a shutdown signal, so
+// long-lived WebSocket loops can drain and exit instead of holding the
+// server open forever. Set once in main before the server starts serving.
+var appCtx context.Context
+
 var httpConfig *config.HTTPConfig
 var corsMiddleware *middleware.CORSMiddleware
+var rateLimitMiddleware *middleware.RateLimitMiddleware
+var gzipMiddleware *middleware.GzipMiddleware
+var authMiddleware *middleware.TenantAuthMiddleware
+var ingestQueueMiddleware *middleware.IngestQueueMiddleware
 var kafkaProducer *kafka.Producer
+var kafkaAdmin *kafka.Admin
 var platformClient *platform.Client
+var schemaRegistry schema.Registry
+var schemaPolicy schema.PolicyConfig
+var bronzeSink *bronze.Sink
+var bronzeDrainSink *kafka.Sink
+
+// schemaHashFor detects data's fields and fingerprints them through the
+// active schema registry, so every caller (bronze writer, Kafka producer,
+// HTTP responses) agrees on the same hash for the same shape.
+func schemaHashFor(data map[string]interface{}) string {
+	return schemaRegistry.Fingerprint(schemaRegistry.Detect(data))
+}
 
-// processIncomingData handles the main logic for processing incoming data
-func processIncomingData(tenantID string, data map[string]interface{}) error {
-	// Generate schema hash
-	schemaHash := generateSchemaHash(data)
+// processIncomingData handles the main logic for processing incoming data.
+// ctx is the originating HTTP request's (or WebSocket message loop's)
+// context, threaded through to every network call this function makes so a
+// client disconnect or server shutdown aborts them instead of leaking a
+// goroutine blocked on a stuck platform API or Kafka broker.
+func processIncomingData(ctx context.Context, tenantID string, data map[string]interface{}) error {
+	ctx, span := tracing.Tracer.Start(ctx, "ingest.process", trace.WithAttributes(
+		attribute.String("tenant.id", tenantID),
+	))
+	defer span.End()
+
+	fields := schemaRegistry.Detect(data)
+	schemaHash := schemaRegistry.Fingerprint(fields)
+	span.SetAttributes(attribute.String("schema.hash", schemaHash))
 
 	// Check if schema exists and is confirmed
-	existingSchema, err := platformClient.GetSchemaByHash(schemaHash, tenantID)
+	existingSchema, err := schemaRegistry.Lookup(ctx, schemaHash, tenantID)
 	if err != nil {
 		log.Printf("Error checking existing schema: %v", err)
-		// Continue processing even if API call fails
+		// Continue processing even if the registry lookup fails
 	}
 
 	if existingSchema != nil && existingSchema.Status == "confirmed" {
 		// Schema is confirmed, process normally
 		log.Printf("Schema %s is confirmed, processing data normally", schemaHash)
-		return storeBronzeForTenant(tenantID, data)
+		metrics.SchemaDetectedTotal.WithLabelValues(tenantID, "confirmed").Inc()
+		return storeBronzeForTenant(tenantID, schemaHash, data)
 	}
 
 	// Schema is not confirmed or doesn't exist
 	if existingSchema == nil {
-		// Create new schema confirmation request
-		detectedFields := detectFields(data, "")
-
-		// Convert detected fields to the format expected by platform API
-		var platformFields []map[string]interface{}
-		for _, field := range detectedFields {
-			platformFields = append(platformFields, map[string]interface{}{
-				"name":         field.Name,
-				"type":         field.Type,
-				"sample_value": field.SampleValue,
-				"required":     field.Required,
-			})
-		}
-
-		// Generate Kafka topic name
-		kafkaTopic := fmt.Sprintf("schema-%s", schemaHash)
-
-		createReq := platform.CreateSchemaRequest{
-			Hash:           schemaHash,
-			KafkaTopic:     kafkaTopic,
-			TenantID:       tenantID,
-			SampleData:     data,
-			DetectedFields: platformFields,
+		metrics.SchemaDetectedTotal.WithLabelValues(tenantID, "new").Inc()
+		if evolved, ok := tryEvolveSchema(ctx, tenantID, fields); ok {
+			log.Printf("Evolved schema %s to version %d for tenant %s, reusing its topic and bronze file", evolved.Hash, evolved.Version, tenantID)
+			return storeBronzeForTenant(tenantID, evolved.Hash, data)
 		}
 
-		_, err := platformClient.CreateSchema(createReq)
+		// Create new schema confirmation request
+		_, err := schemaRegistry.Register(ctx, schema.RegisterRequest{
+			TenantID:   tenantID,
+			SampleData: data,
+			Fields:     fields,
+		})
 		if err != nil {
 			log.Printf("Error creating schema confirmation: %v", err)
 			// Continue to queue the data even if schema creation fails
@@ -88,17 +167,20 @@ func processIncomingData(tenantID string, data map[string]interface{}) error {
 		if err != nil {
 			log.Printf("Error creating Kafka topic for schema %s: %v", schemaHash, err)
 		}
+	} else {
+		metrics.SchemaDetectedTotal.WithLabelValues(tenantID, "pending").Inc()
 	}
 
 	// Queue the data in Kafka until schema is confirmed
 	log.Printf("Queuing data in Kafka for unconfirmed schema: %s", schemaHash)
-	err = kafkaProducer.SendRecord(tenantID, schemaHash, data)
+	err = kafkaProducer.SendRecord(ctx, tenantID, schemaHash, data)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
 	// Increment the pending records count in the platform database
-	err = platformClient.IncrementPendingRecords(schemaHash, tenantID)
+	err = platformClient.IncrementPendingRecords(ctx, schemaHash, tenantID)
 	if err != nil {
 		log.Printf("Error incrementing pending records for schema %s: %v", schemaHash, err)
 		// Don't fail the entire operation if pending records increment fails
@@ -107,388 +189,84 @@ func processIncomingData(tenantID string, data map[string]interface{}) error {
 	return nil
 }
 
-// generateSchemaHash creates a consistent hash for the data structure
-func generateSchemaHash(data map[string]interface{}) string {
-	// Extract field names and types to create a consistent schema signature
-	var fields []string
-	extractFields(data, "", &fields)
-
-	// Sort fields for consistent hashing
-	sort.Strings(fields)
-
-	// Create hash
-	h := sha256.New()
-	h.Write([]byte(strings.Join(fields, "|")))
-	return hex.EncodeToString(h.Sum(nil))[:16] // Use first 16 chars for readability
-}
-
-// extractFields recursively extracts field names and types from nested data
-func extractFields(data map[string]interface{}, prefix string, fields *[]string) {
-	for key, value := range data {
-		fieldName := key
-		if prefix != "" {
-			fieldName = fmt.Sprintf("%s.%s", prefix, key)
-		}
-
-		if nestedMap, ok := value.(map[string]interface{}); ok {
-			// Recursively process nested objects
-			extractFields(nestedMap, fieldName, fields)
-		} else {
-			// Add field with its type
-			fieldType := getValueType(value)
-			*fields = append(*fields, fmt.Sprintf("%s:%s", fieldName, fieldType))
-		}
-	}
-}
-
-// getValueType returns a simplified type string for consistent hashing
-func getValueType(value interface{}) string {
-	switch value.(type) {
-	case nil:
-		return "null"
-	case bool:
-		return "boolean"
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return "integer"
-	case float32, float64:
-		return "float"
-	case string:
-		return "string"
-	case []interface{}:
-		return "array"
-	case map[string]interface{}:
-		return "object"
-	default:
-		return "mixed"
+// tryEvolveSchema checks whether fields is a compatible evolution of one of
+// tenantID's confirmed schemas under the configured compatibility policy,
+// and if so evolves that schema in place instead of registering a new one.
+func tryEvolveSchema(ctx context.Context, tenantID string, fields []schema.Field) (*schema.Record, bool) {
+	mode := schemaPolicy.ModeFor(tenantID)
+	if mode == schema.CompatibilityNone {
+		return nil, false
 	}
-}
-
-type Field struct {
-	Name        string                 `json:"name"`
-	Type        string                 `json:"type"`
-	SampleValue interface{}            `json:"sample_value"`
-	Required    bool                   `json:"required"`
-	Format      string                 `json:"format,omitempty"`       // e.g., "YYYY-MM-DD", "###.##"
-	Pattern     string                 `json:"pattern,omitempty"`      // regex pattern
-	Precision   int                    `json:"precision,omitempty"`    // decimal places
-	Scale       int                    `json:"scale,omitempty"`        // total digits
-	MinValue    *float64               `json:"min_value,omitempty"`    // min observed value
-	MaxValue    *float64               `json:"max_value,omitempty"`    // max observed value
-	Constraints map[string]interface{} `json:"constraints,omitempty"` // additional constraints
-}
-
-// detectFields analyzes data and detects field types with metadata
-func detectFields(data map[string]interface{}, prefix string) []Field {
-	var fields []Field
 
-	for key, value := range data {
-		fieldName := key
-		if prefix != "" {
-			fieldName = fmt.Sprintf("%s.%s", prefix, key)
-		}
-
-		if nestedMap, ok := value.(map[string]interface{}); ok {
-			// Recursively detect nested fields
-			nestedFields := detectFields(nestedMap, fieldName)
-			fields = append(fields, nestedFields...)
-		} else {
-			// Detect field type with metadata
-			fieldType, format, precision, minVal, maxVal := detectFieldType(value)
-
-			field := Field{
-				Name:        fieldName,
-				Type:        fieldType,
-				SampleValue: value,
-				Required:    value != nil, // Required if not null
-				Format:      format,
-				Precision:   precision,
-				MinValue:    minVal,
-				MaxValue:    maxVal,
-			}
-
-			// Add scale for numeric types (total number of digits)
-			if precision > 0 && (fieldType == "float" || fieldType == "integer") {
-				if minVal != nil {
-					totalDigits := len(fmt.Sprintf("%.0f", *minVal))
-					field.Scale = totalDigits + precision
-				}
-			}
-
-			fields = append(fields, field)
-		}
+	confirmed, err := schemaRegistry.ConfirmedForTenant(ctx, tenantID)
+	if err != nil {
+		log.Printf("Error loading confirmed schemas for tenant %s: %v", tenantID, err)
+		return nil, false
 	}
 
-	return fields
-}
-
-// detectFieldType returns the type and metadata for a field value
-func detectFieldType(value interface{}) (fieldType string, format string, precision int, minVal *float64, maxVal *float64) {
-	if value == nil {
-		return "null", "", 0, nil, nil
-	}
-
-	switch v := value.(type) {
-	case bool:
-		return "boolean", "", 0, nil, nil
-	case int:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case int8:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case int16:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case int32:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case int64:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case uint:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case uint8:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case uint16:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case uint32:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case uint64:
-		val := float64(v)
-		return "integer", "", 0, &val, &val
-	case float32:
-		floatVal := float64(v)
-		prec := detectFloatPrecision(floatVal)
-		return "float", "", prec, &floatVal, &floatVal
-	case float64:
-		prec := detectFloatPrecision(v)
-		return "float", "", prec, &v, &v
-	case string:
-		strType, strFormat := detectStringType(v)
-		return strType, strFormat, 0, nil, nil
-	case []interface{}:
-		arrayType := detectArrayType(v)
-		return arrayType, "", 0, nil, nil
-	case map[string]interface{}:
-		return "object", "", 0, nil, nil
-	default:
-		return "string", "", 0, nil, nil
+	decision := schema.DecideEvolution(fields, confirmed, mode)
+	if !decision.Evolve {
+		return nil, false
 	}
-}
 
-// detectFloatPrecision returns the number of decimal places in a float
-func detectFloatPrecision(f float64) int {
-	s := fmt.Sprintf("%f", f)
-	// Trim trailing zeros
-	s = strings.TrimRight(s, "0")
-	s = strings.TrimRight(s, ".")
-
-	parts := strings.Split(s, ".")
-	if len(parts) == 2 {
-		return len(parts[1])
+	evolved, err := schemaRegistry.Evolve(ctx, decision.Target.Hash, tenantID, fields)
+	if err != nil {
+		log.Printf("Error evolving schema %s for tenant %s: %v", decision.Target.Hash, tenantID, err)
+		return nil, false
 	}
-	return 0
+	return evolved, true
 }
 
-func detectArrayType(arr []interface{}) string {
-	if len(arr) == 0 {
-		return "array[empty]"
-	}
-
-	// Analyze first few elements to determine array type
-	sampleSize := len(arr)
-	if sampleSize > 3 {
-		sampleSize = 3
-	}
-
-	typeMap := make(map[string]int)
-	for i := 0; i < sampleSize; i++ {
-		elementType, _, _, _, _ := detectFieldType(arr[i])
-		typeMap[elementType]++
-	}
-
-	// Find the most common type
-	var dominantType string
-	maxCount := 0
-	for t, count := range typeMap {
-		if count > maxCount {
-			maxCount = count
-			dominantType = t
-		}
+// confirmedSchemaHashes adapts schemaRegistry to kafka.ConfirmedTopicsFunc,
+// so the bronze-drain Sink knows which schema-<hash> topics it's safe to
+// consume for tenantID.
+func confirmedSchemaHashes(ctx context.Context, tenantID string) ([]string, error) {
+	confirmed, err := schemaRegistry.ConfirmedForTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
 	}
-
-	// Return array type with element information
-	if len(typeMap) == 1 {
-		return fmt.Sprintf("array[%s]", dominantType)
-	} else {
-		return fmt.Sprintf("array[mixed:%s]", dominantType)
+	hashes := make([]string, len(confirmed))
+	for i, record := range confirmed {
+		hashes[i] = record.Hash
 	}
+	return hashes, nil
 }
 
-// detectStringType determines the specific type of string and returns type and format
-func detectStringType(s string) (string, string) {
-	// Email detection
-	if strings.Contains(s, "@") && strings.Contains(s, ".") {
-		return "email", ""
-	}
-
-	// URL detection
-	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
-		return "url", ""
-	}
-
-	// UUID detection (basic pattern)
-	if len(s) == 36 && strings.Count(s, "-") == 4 {
-		return "uuid", ""
-	}
-
-	// Date/DateTime detection with format capture
-	if dateType, format := detectDateFormat(s); dateType != "" {
-		return dateType, format
-	}
-
-	// JSON detection
-	if (strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")) ||
-		(strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]")) {
-		return "json", ""
-	}
-
-	// Phone number detection (basic)
-	if len(s) >= 10 && strings.ContainsAny(s, "0123456789") {
-		digitCount := 0
-		for _, r := range s {
-			if r >= '0' && r <= '9' {
-				digitCount++
-			}
-		}
-		if digitCount >= 10 && digitCount <= 15 {
-			return "phone", ""
-		}
+// newBronzeStore builds the bronze.ObjectStore selected by cfg.Backend.
+func newBronzeStore(cfg *config.BronzeConfig) (bronze.ObjectStore, error) {
+	switch cfg.Backend {
+	case "s3":
+		return bronze.NewS3Store(bronze.S3StoreConfig{
+			Bucket:          cfg.S3Bucket,
+			Region:          cfg.S3Region,
+			Endpoint:        cfg.S3Endpoint,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			ForcePathStyle:  cfg.S3ForcePathStyle,
+		})
+	default:
+		return bronze.NewLocalStore(cfg.LocalDir), nil
 	}
-
-	return "string", ""
 }
 
-// detectDateFormat detects date/datetime formats and returns the type and format string
-func detectDateFormat(s string) (string, string) {
-	if len(s) < 8 {
-		return "", ""
-	}
-
-	// ISO 8601 datetime with timezone (YYYY-MM-DDTHH:mm:ssZ or variations)
-	if strings.Contains(s, "T") {
-		if strings.HasSuffix(s, "Z") {
-			return "datetime", "ISO8601-UTC"
-		}
-		if strings.Contains(s, "+") || strings.LastIndex(s, "-") > 10 {
-			return "datetime", "ISO8601-TZ"
-		}
-		if len(s) >= 19 { // YYYY-MM-DDTHH:mm:ss
-			return "datetime", "ISO8601"
-		}
-	}
-
-	// Common date patterns
-	if len(s) == 10 {
-		// YYYY-MM-DD
-		if s[4] == '-' && s[7] == '-' {
-			return "date", "YYYY-MM-DD"
-		}
-		// DD/MM/YYYY or MM/DD/YYYY
-		if s[2] == '/' && s[5] == '/' {
-			return "date", "DD/MM/YYYY"
-		}
-		// DD-MM-YYYY
-		if s[2] == '-' && s[5] == '-' {
-			return "date", "DD-MM-YYYY"
-		}
-		// YYYY/MM/DD
-		if s[4] == '/' && s[7] == '/' {
-			return "date", "YYYY/MM/DD"
-		}
-	}
-
-	// DateTime with space separator (YYYY-MM-DD HH:mm:ss)
-	if len(s) == 19 && s[4] == '-' && s[7] == '-' && s[10] == ' ' && s[13] == ':' && s[16] == ':' {
-		return "datetime", "YYYY-MM-DD HH:mm:ss"
-	}
-
-	// DateTime with T separator but no timezone (YYYY-MM-DDTHH:mm:ss)
-	if len(s) == 19 && s[4] == '-' && s[7] == '-' && s[10] == 'T' && s[13] == ':' && s[16] == ':' {
-		return "datetime", "YYYY-MM-DDTHH:mm:ss"
-	}
-
-	// DD/MM/YYYY HH:mm:ss
-	if len(s) == 19 && s[2] == '/' && s[5] == '/' && s[10] == ' ' && s[13] == ':' && s[16] == ':' {
-		return "datetime", "DD/MM/YYYY HH:mm:ss"
-	}
-
-	// Unix timestamp (10 digits for seconds, 13 for milliseconds)
-	if len(s) == 10 || len(s) == 13 {
-		allDigits := true
-		for _, r := range s {
-			if r < '0' || r > '9' {
-				allDigits = false
-				break
-			}
-		}
-		if allDigits {
-			if len(s) == 10 {
-				return "timestamp", "unix-seconds"
-			}
-			return "timestamp", "unix-milliseconds"
-		}
-	}
-
-	return "", ""
+// storeBronzeForTenant buffers data into the Parquet bronze sink under
+// tenantID/hash's Hive-style partition, rolling that partition's current
+// file once the configured size or age threshold is hit.
+func storeBronzeForTenant(tenantID, hash string, data map[string]interface{}) error {
+	start := time.Now()
+	defer func() {
+		metrics.BronzeWriteSeconds.Observe(time.Since(start).Seconds())
+	}()
+
+	fields := schemaRegistry.Detect(data)
+	return bronzeSink.Write(tenantID, hash, fields, data)
 }
 
-func storeBronzeForTenant(tenantID string, data map[string]interface{}) error {
-	hash := generateSchemaHash(data)
-	dir := filepath.Join("bronze", fmt.Sprintf("tenant_%s", tenantID))
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-	filePath := filepath.Join(dir, hash+".csv")
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	w := csv.NewWriter(f)
-	stat, _ := os.Stat(filePath)
-	isNew := stat == nil || stat.Size() == 0
-	var keys []string
-	if isNew {
-		keys = make([]string, 0, len(data))
-		for k := range data {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		if err := w.Write(keys); err != nil {
-			return err
-		}
-	} else {
-		// For simplicity, assume same schema, not reading headers
-		keys = make([]string, 0, len(data))
-		for k := range data {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-	}
-	values := make([]string, len(keys))
-	for i, k := range keys {
-		values[i] = fmt.Sprintf("%v", data[k])
-	}
-	if err := w.Write(values); err != nil {
-		return err
-	}
-	w.Flush()
-	return w.Error()
+// wsReadResult carries one conn.ReadMessage() result across goroutines, so
+// the handler's select loop can race it against ctx cancellation.
+type wsReadResult struct {
+	message []byte
+	err     error
 }
 
 func tenantWebsocketHandler(w http.ResponseWriter, r *http.Request) {
@@ -509,25 +287,59 @@ func tenantWebsocketHandler(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Printf("WebSocket connection established for tenant %s\n", tenantID)
 
-	for {
-		_, message, err := conn.ReadMessage()
-		if err != nil {
-			break
-		}
-		var data map[string]interface{}
-		if err := json.Unmarshal(message, &data); err != nil {
-			continue
+	metrics.WebsocketConnections.WithLabelValues(tenantID).Inc()
+	defer metrics.WebsocketConnections.WithLabelValues(tenantID).Dec()
+
+	ctx, cancel := context.WithCancel(appCtx)
+	defer cancel()
+
+	deadlines := newWSDeadlines(conn)
+	go deadlines.keepalive(ctx)
+
+	reads := make(chan wsReadResult)
+	go func() {
+		for {
+			_, message, err := conn.ReadMessage()
+			select {
+			case reads <- wsReadResult{message: message, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
 		}
+	}()
 
-		// Generate schema hash and process data
-		hash := generateSchemaHash(data)
+	for {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(wsWriteWait))
+			return
+
+		case read := <-reads:
+			if read.err != nil {
+				return
+			}
+			var data map[string]interface{}
+			if err := json.Unmarshal(read.message, &data); err != nil {
+				continue
+			}
 
-		// Process the data using new Kafka-based logic
-		if err := processIncomingData(tenantID, data); err != nil {
-			log.Printf("Error processing WebSocket data for tenant %s: %v", tenantID, err)
-			continue
+			// Generate schema hash and process data
+			hash := schemaHashFor(data)
+
+			// Process the data using new Kafka-based logic
+			if err := processIncomingData(ctx, tenantID, data); err != nil {
+				log.Printf("Error processing WebSocket data for tenant %s: %v", tenantID, err)
+				metrics.IngestRequestsTotal.WithLabelValues(tenantID, "error").Inc()
+				continue
+			}
+			metrics.IngestRequestsTotal.WithLabelValues(tenantID, "success").Inc()
+			fmt.Printf("WebSocket: Received data for tenant %s with schema hash: %s\n", tenantID, hash)
 		}
-		fmt.Printf("WebSocket: Received data for tenant %s with schema hash: %s\n", tenantID, hash)
 	}
 }
 
@@ -562,14 +374,16 @@ func tenantIngestHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate schema hash and process data
-	hash := generateSchemaHash(data)
+	hash := schemaHashFor(data)
 
 	// Process the data using new Kafka-based logic
-	if err := processIncomingData(tenantID, data); err != nil {
+	if err := processIncomingData(r.Context(), tenantID, data); err != nil {
 		log.Printf("Error processing data for tenant %s: %v", tenantID, err)
+		metrics.IngestRequestsTotal.WithLabelValues(tenantID, "error").Inc()
 		http.Error(w, "Failed to process data", http.StatusInternalServerError)
 		return
 	}
+	metrics.IngestRequestsTotal.WithLabelValues(tenantID, "success").Inc()
 	fmt.Printf("POST: Received data for tenant %s with schema hash: %s\n", tenantID, hash)
 
 	response := map[string]interface{}{
@@ -615,17 +429,226 @@ func kafkaTopicCountHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// kafkaAdminTopicsHandler lists all schema-* topics with their partition
+// counts and retention. Use GET /kafka/admin/topics.
+func kafkaAdminTopicsHandler(w http.ResponseWriter, r *http.Request) {
+	topics, err := kafkaAdmin.ListSchemaTopics()
+	if err != nil {
+		log.Printf("Error listing schema topics: %v", err)
+		http.Error(w, "Failed to list schema topics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(topics)
+}
+
+// kafkaAdminTopicConfigHandler describes or alters the config of the topic
+// backing a schema hash. GET returns the current ConfigEntries, PUT applies
+// a { "config.name": "value", ... } body.
+// Use /kafka/admin/topics/{hash}/config.
+func kafkaAdminTopicConfigHandler(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 6 {
+		http.Error(w, "Invalid URL format. Use /kafka/admin/topics/{hash}/config", http.StatusBadRequest)
+		return
+	}
+	schemaHash := pathParts[4]
+
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := kafkaAdmin.DescribeTopicConfig(schemaHash)
+		if err != nil {
+			log.Printf("Error describing config for schema %s: %v", schemaHash, err)
+			http.Error(w, "Failed to describe topic config", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+
+	case http.MethodPut:
+		var entries map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := kafkaAdmin.AlterTopicConfig(schemaHash, entries); err != nil {
+			log.Printf("Error altering config for schema %s: %v", schemaHash, err)
+			http.Error(w, "Failed to alter topic config", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// kafkaAdminReassignmentsHandler lists or triggers KIP-455 partition
+// reassignments for the topic backing a schema hash. GET lists in-progress
+// reassignments, POST applies a [{ "partition": N, "broker_ids": [...] }]
+// body. Use /kafka/admin/topics/{hash}/reassignments.
+func kafkaAdminReassignmentsHandler(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 6 {
+		http.Error(w, "Invalid URL format. Use /kafka/admin/topics/{hash}/reassignments", http.StatusBadRequest)
+		return
+	}
+	schemaHash := pathParts[4]
+
+	switch r.Method {
+	case http.MethodGet:
+		assignments, err := kafkaAdmin.ListPartitionReassignments(schemaHash)
+		if err != nil {
+			log.Printf("Error listing partition reassignments for schema %s: %v", schemaHash, err)
+			http.Error(w, "Failed to list partition reassignments", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(assignments)
+
+	case http.MethodPost:
+		var assignments []kafka.PartitionAssignment
+		if err := json.NewDecoder(r.Body).Decode(&assignments); err != nil {
+			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			return
+		}
+		if err := kafkaAdmin.AlterPartitionReassignments(schemaHash, assignments); err != nil {
+			log.Printf("Error altering partition reassignments for schema %s: %v", schemaHash, err)
+			http.Error(w, "Failed to alter partition reassignments", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "reassignment_triggered"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// schemaVersionsHandler returns a tenant's schema version history, oldest
+// first. Use GET /tenant/{tenant_id}/schema/{hash}/versions.
+func schemaVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 6 || pathParts[3] != "schema" || pathParts[5] != "versions" {
+		http.Error(w, "Invalid URL format. Use /tenant/{tenant_id}/schema/{hash}/versions", http.StatusBadRequest)
+		return
+	}
+	tenantID := pathParts[2]
+	schemaHash := pathParts[4]
+
+	versions, err := schemaRegistry.Versions(r.Context(), schemaHash, tenantID)
+	if err != nil {
+		log.Printf("Error listing schema versions for tenant %s, hash %s: %v", tenantID, schemaHash, err)
+		http.Error(w, "Failed to list schema versions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
 func main() {
+	// appCtx is canceled on SIGTERM/SIGINT so the bronze-drain Sink, any
+	// open WebSocket connections, and the tracer provider can wind down
+	// instead of being dropped.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	appCtx = ctx
+
+	tracingShutdown, err := tracing.Init(ctx, config.LoadTracingConfig())
+	if err != nil {
+		log.Printf("tracing disabled: failed to initialize: %v", err)
+		tracingShutdown = func(context.Context) error { return nil }
+	}
+
 	// Load configuration
 	httpConfig = config.LoadHTTPConfig()
 	corsMiddleware = middleware.NewCORSMiddleware(&httpConfig.CORS)
+	rateLimitMiddleware = middleware.NewRateLimitMiddleware(&httpConfig.RateLimiting, middleware.TenantPathKey)
+	gzipMiddleware = middleware.NewGzipMiddleware(&httpConfig.Gzip)
+	authMiddleware = middleware.NewTenantAuthMiddleware(config.LoadAuthConfig())
+	ingestQueueMiddleware = middleware.NewIngestQueueMiddleware(config.LoadIngestQueueConfig())
 
 	// Initialize Kafka
-	kafkaProducer = kafka.NewProducer([]string{"kafka:29092"})
+	kafkaConfig := config.LoadKafkaConfig()
+	kafkaSecurity := kafka.SecurityConfig{
+		SASL:  kafka.SASLMechanism(kafkaConfig.Security.SASLMechanism),
+		Plain: kafka.UsernamePassword{Username: kafkaConfig.Security.SASLUsername, Password: kafkaConfig.Security.SASLPassword},
+		Scram: kafka.UsernamePassword{Username: kafkaConfig.Security.SASLUsername, Password: kafkaConfig.Security.SASLPassword},
+		OAuth: kafka.OAuthConfig{
+			ClientID:     kafkaConfig.Security.OAuthClientID,
+			ClientSecret: kafkaConfig.Security.OAuthClientSecret,
+			TokenURL:     kafkaConfig.Security.OAuthTokenURL,
+			Scopes:       kafkaConfig.Security.OAuthScopes,
+		},
+		TLS: kafka.TLSConfig{
+			Enabled:  kafkaConfig.Security.TLSEnabled,
+			CertFile: kafkaConfig.Security.TLSCertFile,
+			KeyFile:  kafkaConfig.Security.TLSKeyFile,
+			CAFile:   kafkaConfig.Security.TLSCAFile,
+		},
+	}
+	kafkaAdmin = kafka.NewAdmin(kafkaConfig.Brokers, kafkaSecurity)
 
 	// Initialize Platform API client
 	platformClient = platform.NewClient("http://platform")
 
+	// Schema registry defaults to the platform API backend; swap in
+	// schema.NewConfluentRegistry/schema.NewFileRegistry for other deployments.
+	schemaRegistry = schema.NewPlatformRegistry(platformClient)
+
+	// Only ConfluentRegistry defines a wire format other than plain JSON;
+	// every other backend leaves this nil and the producer/consumers write
+	// and read raw JSON, as before.
+	var schemaSerializer kafka.Serializer
+	if confluentRegistry, ok := schemaRegistry.(*schema.ConfluentRegistry); ok {
+		schemaSerializer = schema.NewConfluentSerializer(confluentRegistry)
+	}
+
+	kafkaProducer = kafka.NewProducer(kafkaConfig.Brokers, kafka.ProducerConfig{
+		Compression:     kafkaConfig.Compression,
+		ApplicationGzip: kafkaConfig.ApplicationGzip,
+		Security:        kafkaSecurity,
+		Serializer:      schemaSerializer,
+	})
+
+	// Per-tenant schema compatibility policy for auto-evolving schemas
+	// instead of always queueing a new pending confirmation.
+	schemaConfig := config.LoadSchemaConfig()
+	schemaPolicy = schema.ParsePolicyConfig(schemaConfig.CompatibilityMode, schemaConfig.CompatibilityModeOverrides)
+
+	// Bronze sink: Parquet partitions on the configured object store backend.
+	bronzeConfig := config.LoadBronzeConfig()
+	bronzeStore, err := newBronzeStore(bronzeConfig)
+	if err != nil {
+		log.Fatalf("failed to initialize bronze store: %v", err)
+	}
+	bronzeSink = bronze.NewSink(bronzeStore, bronze.Config{
+		MaxBytes: int64(bronzeConfig.RollMaxBytes),
+		MaxAge:   time.Duration(bronzeConfig.RollMaxAgeSeconds) * time.Second,
+	})
+
+	// Bronze-drain sink: drains every confirmed schema topic's Kafka backlog
+	// into the bronze writer, so queued records aren't stuck waiting for the
+	// next HTTP request on that tenant/schema to flush them.
+	sinkConfig := config.LoadSinkConfig()
+	bronzeDrainSink = kafka.NewSink(kafka.SinkConfig{
+		Brokers:       kafkaConfig.Brokers,
+		Security:      kafkaSecurity,
+		Tenants:       sinkConfig.Tenants,
+		PollInterval:  time.Duration(sinkConfig.PollIntervalSeconds) * time.Second,
+		MaxRetries:    sinkConfig.MaxRetries,
+		RetryBaseWait: time.Duration(sinkConfig.RetryBaseWaitMillis) * time.Millisecond,
+		Serializer:    schemaSerializer,
+	}, confirmedSchemaHashes, storeBronzeForTenant)
+
 	// Root endpoint with CORS
 	http.HandleFunc("/", corsMiddleware.Handler(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "AI Data Lake Ingestion Service\n\nEndpoints:\n- POST /tenant/{tenant_id}/ingest\n- WebSocket /tenant/{tenant_id}/ws\n\nCORS Configuration:\n- Allowed Origins: %v\n- Allowed Methods: %v",
@@ -639,36 +662,95 @@ func main() {
 	}))
 
 	// Kafka topic count endpoint
-	http.HandleFunc("/kafka/topic/", func(w http.ResponseWriter, r *http.Request) {
+	http.HandleFunc("/kafka/topic/", gzipMiddleware.Handler(func(w http.ResponseWriter, r *http.Request) {
 		if strings.HasSuffix(r.URL.Path, "/count") {
 			kafkaTopicCountHandler(w, r)
 		} else {
 			http.Error(w, "Invalid Kafka endpoint", http.StatusNotFound)
 		}
-	})
+	}))
+
+	// Kafka admin endpoints. These read and mutate broker-wide topic
+	// config and partition placement, so they require a token with the
+	// admin scope rather than just any authenticated tenant.
+	http.HandleFunc("/kafka/admin/topics", corsMiddleware.Handler(gzipMiddleware.Handler(authMiddleware.AdminHandler(kafkaAdminTopicsHandler))))
+	http.HandleFunc("/kafka/admin/topics/", corsMiddleware.Handler(gzipMiddleware.Handler(authMiddleware.AdminHandler(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/config"):
+			kafkaAdminTopicConfigHandler(w, r)
+		case strings.HasSuffix(r.URL.Path, "/reassignments"):
+			kafkaAdminReassignmentsHandler(w, r)
+		default:
+			http.Error(w, "Invalid Kafka admin endpoint", http.StatusNotFound)
+		}
+	}))))
 
-	// Tenant-specific endpoints
-	http.HandleFunc("/tenant/", func(w http.ResponseWriter, r *http.Request) {
+	// Tenant-specific endpoints. Wrapped in otelhttp.NewHandler so a
+	// traceparent header on the incoming request continues that trace
+	// through processIncomingData, Kafka, and out to the drain consumer.
+	http.Handle("/tenant/", otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
-		if strings.HasSuffix(path, "/ingest") {
-			tenantIngestHandler(w, r)
-		} else if strings.HasSuffix(path, "/ws") {
-			tenantWebsocketHandler(w, r)
-		} else {
+		switch {
+		case strings.HasSuffix(path, "/ingest"):
+			gzipMiddleware.Handler(authMiddleware.Handler(rateLimitMiddleware.Handler(ingestQueueMiddleware.Handler(tenantIngestHandler))))(w, r)
+		case strings.HasSuffix(path, "/ws"):
+			authMiddleware.Handler(rateLimitMiddleware.Handler(ingestQueueMiddleware.Handler(tenantWebsocketHandler)))(w, r)
+		case strings.HasSuffix(path, "/versions"):
+			gzipMiddleware.Handler(schemaVersionsHandler)(w, r)
+		default:
 			// Apply CORS to error responses too
 			middleware.ApplyCORSHeaders(w, &httpConfig.CORS, r.Header.Get("Origin"))
-			http.Error(w, "Invalid endpoint. Use /tenant/{tenant_id}/ingest or /tenant/{tenant_id}/ws", http.StatusNotFound)
+			http.Error(w, "Invalid endpoint. Use /tenant/{tenant_id}/ingest, /tenant/{tenant_id}/ws, or /tenant/{tenant_id}/schema/{hash}/versions", http.StatusNotFound)
 		}
-	})
+	}), "tenant"))
+
+	// Metrics endpoint for Prometheus scraping.
+	http.Handle("/metrics", metrics.Handler())
 
 	fmt.Printf("Starting ingestion service on :%s\n", httpConfig.Port)
 	fmt.Println("Endpoints:")
 	fmt.Println("- POST /tenant/{tenant_id}/ingest")
 	fmt.Println("- WebSocket /tenant/{tenant_id}/ws")
 	fmt.Println("- GET /health")
+	fmt.Println("- GET /metrics")
 	fmt.Printf("CORS Origins: %v\n", httpConfig.CORS.AllowedOrigins)
 
-	if err := http.ListenAndServe(":"+httpConfig.Port, nil); err != nil {
-		panic(err)
+	go bronzeSink.Run(ctx)
+	go bronzeDrainSink.Run(ctx)
+
+	server := &http.Server{Addr: ":" + httpConfig.Port}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatalf("ingestion server failed: %v", err)
+		}
+		return
+	case <-ctx.Done():
+	}
+
+	log.Println("shutdown signal received, draining in-flight requests")
+	stop() // restore default signal handling so a second SIGTERM force-kills
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("error during graceful shutdown: %v", err)
+	}
+
+	if err := bronzeSink.Flush(); err != nil {
+		log.Printf("error flushing bronze sink on shutdown: %v", err)
+	}
+
+	if err := kafkaProducer.Close(); err != nil {
+		log.Printf("error closing Kafka producer on shutdown: %v", err)
+	}
+
+	if err := tracingShutdown(shutdownCtx); err != nil {
+		log.Printf("error shutting down tracer provider: %v", err)
 	}
 }
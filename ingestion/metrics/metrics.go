@@ -0,0 +1,64 @@
+// Package metrics holds the ingestion service's Prometheus collectors.
+// Every metric is registered against the default registry at package init,
+// so callers just record observations; Handler exposes them for scraping.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// IngestRequestsTotal counts ingest requests per tenant, labeled with
+	// the outcome ("success" or "error").
+	IngestRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingest_requests_total",
+		Help: "Total ingest requests processed, by tenant and status.",
+	}, []string{"tenant", "status"})
+
+	// SchemaDetectedTotal counts detected schemas per tenant, labeled with
+	// whether the schema was already confirmed, still pending confirmation,
+	// or newly registered.
+	SchemaDetectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "schema_detected_total",
+		Help: "Schemas detected on incoming data, by tenant and status (confirmed|pending|new).",
+	}, []string{"tenant", "status"})
+
+	// KafkaProduceSeconds observes how long a Kafka write takes.
+	KafkaProduceSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "kafka_produce_seconds",
+		Help:    "Time spent writing a record to Kafka.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// PlatformAPISeconds observes platform API call latency, labeled by
+	// endpoint and response status code.
+	PlatformAPISeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "platform_api_seconds",
+		Help:    "Time spent calling the platform API, by endpoint and response code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint", "code"})
+
+	// BronzeWriteSeconds observes how long buffering a row into the bronze
+	// sink takes.
+	BronzeWriteSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "bronze_write_seconds",
+		Help:    "Time spent writing a row to the bronze sink.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// WebsocketConnections tracks currently open WebSocket connections per
+	// tenant.
+	WebsocketConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "websocket_connections",
+		Help: "Currently open WebSocket connections, by tenant.",
+	}, []string{"tenant"})
+)
+
+// Handler serves the default Prometheus registry for GET /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
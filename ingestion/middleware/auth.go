@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// tenantClaims is the JWT claim set TenantAuthMiddleware expects: the
+// standard registered claims, the tenant IDs this token is authorized to
+// ingest for, and its OAuth2-style space-delimited scopes.
+type tenantClaims struct {
+	TenantIDs []string `json:"tenant_ids"`
+	Scope     string   `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+func (c *tenantClaims) allowsTenant(tenantID string) bool {
+	for _, allowed := range c.TenantIDs {
+		if allowed == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// adminScope is the scope a token must carry to use the Kafka admin API:
+// list/describe topic configs and trigger KIP-455 partition reassignments.
+// It's deliberately separate from tenant_ids, since every valid tenant
+// token would otherwise also be an admin token.
+const adminScope = "admin"
+
+func (c *tenantClaims) hasScope(scope string) bool {
+	for _, s := range strings.Fields(c.Scope) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantAuthMiddleware validates an Authorization: Bearer JWT against a
+// JWKS (refreshed on config.AuthConfig.JWKSRefreshInterval) and checks that
+// the token's tenant_ids claim includes the tenant ID in the request path.
+type TenantAuthMiddleware struct {
+	cfg  *config.AuthConfig
+	jwks *keyfunc.JWKS
+}
+
+// NewTenantAuthMiddleware builds a TenantAuthMiddleware from cfg, fetching
+// cfg.JWKSURL once up front. If the initial fetch fails, the middleware
+// still starts (every request will then fail closed with 503) rather than
+// taking down the whole service on a JWKS outage.
+func NewTenantAuthMiddleware(cfg *config.AuthConfig) *TenantAuthMiddleware {
+	m := &TenantAuthMiddleware{cfg: cfg}
+	if !cfg.Enabled {
+		return m
+	}
+
+	jwks, err := keyfunc.Get(cfg.JWKSURL, keyfunc.Options{
+		RefreshInterval: cfg.JWKSRefreshInterval,
+		RefreshErrorHandler: func(err error) {
+			log.Printf("TenantAuth: failed to refresh JWKS from %s: %v", cfg.JWKSURL, err)
+		},
+	})
+	if err != nil {
+		log.Printf("TenantAuth: failed to fetch JWKS from %s: %v", cfg.JWKSURL, err)
+	}
+	m.jwks = jwks
+	return m
+}
+
+// authenticate extracts and validates r's bearer token, returning its
+// claims. The caller still needs to check those claims authorize the
+// specific operation (tenant ID, admin scope, ...); authenticate only
+// establishes that the token is a valid, unexpired token from this issuer.
+func (m *TenantAuthMiddleware) authenticate(w http.ResponseWriter, r *http.Request) (*tenantClaims, bool) {
+	tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || tokenString == "" {
+		http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	if m.jwks == nil {
+		http.Error(w, "Auth unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	claims := &tenantClaims{}
+	// WithValidMethods pins the accepted algorithm to RS256 (what the JWKS
+	// holds public keys for), so a token can't switch to a symmetric or
+	// "none" algorithm and get accepted on the strength of a forged
+	// signature or the JWKS's own public key bytes.
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.jwks.Keyfunc,
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(m.cfg.Issuer),
+		jwt.WithAudience(m.cfg.Audience),
+	)
+	if err != nil || !token.Valid {
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// Handler wraps next with bearer-token authentication. When disabled it
+// calls next unconditionally; otherwise it requires a valid JWT whose
+// tenant_ids claim includes the {tenant_id} from the request path.
+func (m *TenantAuthMiddleware) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.cfg.Enabled {
+			next(w, r)
+			return
+		}
+
+		tenantID, ok := TenantFromPath(r.URL.Path)
+		if !ok {
+			http.Error(w, "Invalid URL format. Use /tenant/{tenant_id}/...", http.StatusBadRequest)
+			return
+		}
+
+		claims, ok := m.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if !claims.allowsTenant(tenantID) {
+			http.Error(w, "Token not authorized for tenant "+tenantID, http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// AdminHandler wraps next with bearer-token authentication requiring the
+// adminScope, for operator-only endpoints (the Kafka admin API) that have
+// no {tenant_id} in their path for Handler's tenant_ids check to use. When
+// disabled it calls next unconditionally, same as Handler.
+func (m *TenantAuthMiddleware) AdminHandler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.cfg.Enabled {
+			next(w, r)
+			return
+		}
+
+		claims, ok := m.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if !claims.hasScope(adminScope) {
+			http.Error(w, "Token missing required admin scope", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// TestAdminHandlerRejectsUnauthenticatedRequests verifies the Kafka admin
+// endpoints reject a request with no bearer token, rather than falling
+// through to next like an unprotected handler would.
+func TestAdminHandlerRejectsUnauthenticatedRequests(t *testing.T) {
+	m := NewTenantAuthMiddleware(&config.AuthConfig{Enabled: true})
+
+	called := false
+	handler := m.AdminHandler(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/kafka/admin/topics", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if called {
+		t.Fatal("admin handler invoked next without a bearer token")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+// newTestJWKSServer starts an httptest server serving priv's public key as a
+// JWKS under kid, so a TenantAuthMiddleware can be pointed at it like a real
+// JWKS endpoint.
+func newTestJWKSServer(t *testing.T, priv *rsa.PrivateKey, kid string) *httptest.Server {
+	t.Helper()
+
+	n := base64.RawURLEncoding.EncodeToString(priv.PublicKey.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}) // 65537
+
+	jwks := map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256", "n": n, "e": e},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestAuthenticateRejectsAlgorithmConfusionToken verifies a token that
+// claims HS256 (signed with the RSA public key's PEM bytes as an HMAC
+// secret - the classic RS256-to-HS256 confusion attack, since a JWKS
+// publishes its public key to anyone) is rejected rather than accepted on
+// the strength of jwt.Keyfunc returning that same public key.
+func TestAuthenticateRejectsAlgorithmConfusionToken(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	const kid = "test-key"
+	jwksServer := newTestJWKSServer(t, priv, kid)
+
+	m := NewTenantAuthMiddleware(&config.AuthConfig{
+		Enabled: true,
+		JWKSURL: jwksServer.URL,
+	})
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tenantClaims{
+		TenantIDs:        []string{"acme"},
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(pubPEM)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/kafka/admin/topics", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	rec := httptest.NewRecorder()
+
+	if _, ok := m.authenticate(rec, req); ok {
+		t.Fatal("authenticate accepted an HS256 token signed with the RSA public key bytes, want rejection")
+	}
+}
+
+// TestAdminHandlerPassesThroughWhenDisabled verifies the existing
+// dev-mode escape hatch (auth disabled entirely) still works for the
+// admin routes, matching Handler's behavior for tenant routes.
+func TestAdminHandlerPassesThroughWhenDisabled(t *testing.T) {
+	m := NewTenantAuthMiddleware(&config.AuthConfig{Enabled: false})
+
+	called := false
+	handler := m.AdminHandler(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/kafka/admin/topics", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if !called {
+		t.Fatal("admin handler did not invoke next with auth disabled")
+	}
+}
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// skipCompressionContentTypes lists content types that are already
+// compressed, so re-compressing them would waste CPU for no benefit.
+var skipCompressionContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+}
+
+// GzipMiddleware transparently gzip-compresses responses when the client
+// sends Accept-Encoding: gzip, skipping small responses and content types
+// that are already compressed.
+type GzipMiddleware struct {
+	cfg *config.GzipConfig
+}
+
+// NewGzipMiddleware builds a GzipMiddleware from cfg.
+func NewGzipMiddleware(cfg *config.GzipConfig) *GzipMiddleware {
+	return &GzipMiddleware{cfg: cfg}
+}
+
+// Handler wraps next, gzip-compressing its response when the client accepts
+// gzip and the response is large enough and not already compressed.
+func (m *GzipMiddleware) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.cfg.Enabled || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{
+			ResponseWriter: w,
+			request:        r,
+			cfg:            m.cfg,
+		}
+		defer gw.Close()
+
+		next(gw, r)
+	}
+}
+
+// gzipResponseWriter buffers the first write so it can decide, based on
+// Content-Type and size, whether to compress at all. Once that decision is
+// made it either streams through a gzip.Writer or falls back to the
+// underlying ResponseWriter unmodified.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	request *http.Request
+	cfg     *config.GzipConfig
+
+	statusCode  int
+	headerSent  bool
+	gz          *gzip.Writer
+	compressing bool
+	passthrough bool
+	buf         []byte
+}
+
+func (g *gzipResponseWriter) WriteHeader(statusCode int) {
+	g.statusCode = statusCode
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if g.passthrough {
+		return g.ResponseWriter.Write(p)
+	}
+	if g.compressing {
+		return g.gz.Write(p)
+	}
+
+	g.buf = append(g.buf, p...)
+
+	if shouldSkipCompression(g.Header().Get("Content-Type")) {
+		return g.flushPassthrough(len(p))
+	}
+	if len(g.buf) >= g.cfg.MinSizeBytes {
+		return g.startCompressing(len(p))
+	}
+	// Wait for more data (or Close) before deciding.
+	return len(p), nil
+}
+
+func (g *gzipResponseWriter) flushPassthrough(lastWriteLen int) (int, error) {
+	g.passthrough = true
+	g.sendHeader()
+	if _, err := g.ResponseWriter.Write(g.buf); err != nil {
+		return 0, err
+	}
+	g.buf = nil
+	return lastWriteLen, nil
+}
+
+func (g *gzipResponseWriter) startCompressing(lastWriteLen int) (int, error) {
+	g.compressing = true
+	level := g.cfg.CompressionLevel
+	if level <= 0 {
+		level = gzip.DefaultCompression
+	}
+	gz, err := gzip.NewWriterLevel(g.ResponseWriter, level)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create gzip writer: %w", err)
+	}
+	g.gz = gz
+
+	g.Header().Set("Content-Encoding", "gzip")
+	g.Header().Add("Vary", "Accept-Encoding")
+	g.Header().Del("Content-Length") // length is no longer known once compressed
+	g.sendHeader()
+
+	if _, err := g.gz.Write(g.buf); err != nil {
+		return 0, err
+	}
+	g.buf = nil
+	return lastWriteLen, nil
+}
+
+func (g *gzipResponseWriter) sendHeader() {
+	if g.headerSent {
+		return
+	}
+	g.headerSent = true
+	if g.statusCode == 0 {
+		g.statusCode = http.StatusOK
+	}
+	g.ResponseWriter.WriteHeader(g.statusCode)
+}
+
+// Close flushes any buffered response that never crossed MinSizeBytes, and
+// closes the gzip stream if compression was started.
+func (g *gzipResponseWriter) Close() error {
+	if g.passthrough {
+		return nil
+	}
+	if g.compressing {
+		return g.gz.Close()
+	}
+	if len(g.buf) > 0 || !g.headerSent {
+		_, err := g.flushPassthrough(0)
+		return err
+	}
+	return nil
+}
+
+// Hijack supports WebSocket upgrades passing through this middleware
+// untouched; compression never applies to hijacked connections.
+func (g *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := g.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+func shouldSkipCompression(contentType string) bool {
+	for _, prefix := range skipCompressionContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// IngestQueueMiddleware bounds how many ingestion requests run at once and
+// how many one tenant may have outstanding, so a slow Kafka broker pins at
+// most cfg.Workers goroutines instead of an unbounded number, and a single
+// noisy tenant can't starve the others out of their share of that pool.
+//
+// Admission is two bounded semaphores: a per-tenant one (size
+// PerTenantQueueSize) that a tenant's own backlog can't exceed, and a
+// global one (size Workers) shared fairly across all tenants' admitted
+// requests. A tenant whose own semaphore is full gets a fast 429 rather
+// than piling up more goroutines behind it.
+type IngestQueueMiddleware struct {
+	cfg *config.IngestQueueConfig
+
+	global chan struct{}
+
+	mu     sync.Mutex
+	tenant map[string]chan struct{}
+}
+
+// NewIngestQueueMiddleware builds an IngestQueueMiddleware from cfg.
+func NewIngestQueueMiddleware(cfg *config.IngestQueueConfig) *IngestQueueMiddleware {
+	return &IngestQueueMiddleware{
+		cfg:    cfg,
+		global: make(chan struct{}, cfg.Workers),
+		tenant: make(map[string]chan struct{}),
+	}
+}
+
+func (m *IngestQueueMiddleware) queueFor(tenantID string) chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.tenant[tenantID]
+	if !ok {
+		q = make(chan struct{}, m.cfg.PerTenantQueueSize)
+		m.tenant[tenantID] = q
+	}
+	return q
+}
+
+// Handler wraps next with admission control. It rejects with 429 and
+// Retry-After when the request's tenant already has PerTenantQueueSize
+// requests queued or in flight; otherwise it waits for a free slot in the
+// global Workers-sized pool (or the request's context being canceled)
+// before calling next.
+func (m *IngestQueueMiddleware) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, ok := TenantFromPath(r.URL.Path)
+		if !ok {
+			http.Error(w, "Invalid URL format. Use /tenant/{tenant_id}/...", http.StatusBadRequest)
+			return
+		}
+
+		tenantQueue := m.queueFor(tenantID)
+		select {
+		case tenantQueue <- struct{}{}:
+		default:
+			w.Header().Set("Retry-After", strconv.Itoa(m.cfg.RetryAfterSeconds))
+			http.Error(w, "Tenant ingestion queue full", http.StatusTooManyRequests)
+			return
+		}
+		defer func() { <-tenantQueue }()
+
+		select {
+		case m.global <- struct{}{}:
+		case <-r.Context().Done():
+			return
+		}
+		defer func() { <-m.global }()
+
+		next(w, r)
+	}
+}
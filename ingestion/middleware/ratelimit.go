@@ -0,0 +1,299 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// defaultLimiterCacheSize bounds how many per-key limiters RateLimitMiddleware
+// keeps in memory at once, so an unbounded set of keys (e.g. client IPs)
+// can't grow the cache forever.
+const defaultLimiterCacheSize = 10000
+
+// KeyFunc extracts the rate-limit key (client IP, tenant ID, API key, ...)
+// from a request.
+type KeyFunc func(r *http.Request) string
+
+// ClientIPKey extracts the client IP, preferring X-Forwarded-For (first hop)
+// and falling back to r.RemoteAddr.
+func ClientIPKey(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(first)
+	}
+	return r.RemoteAddr
+}
+
+// TenantIDKey extracts the tenant ID from the X-Tenant-ID header.
+func TenantIDKey(r *http.Request) string {
+	return r.Header.Get("X-Tenant-ID")
+}
+
+// TenantPathKey extracts the tenant ID from a /tenant/{tenant_id}/... URL,
+// for endpoints that carry it in the path rather than a header.
+func TenantPathKey(r *http.Request) string {
+	tenantID, _ := TenantFromPath(r.URL.Path)
+	return tenantID
+}
+
+// APIKeyKey extracts the caller's API key from the X-API-Key header.
+func APIKeyKey(r *http.Request) string {
+	return r.Header.Get("X-API-Key")
+}
+
+// limiterLRU is a size-bounded cache of per-key rate.Limiters.
+type limiterLRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type limiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func newLimiterLRU(capacity int) *limiterLRU {
+	return &limiterLRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *limiterLRU) getOrCreate(key string, newLimiter func() *rate.Limiter) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*limiterEntry).limiter
+	}
+
+	limiter := newLimiter()
+	elem := c.order.PushFront(&limiterEntry{key: key, limiter: limiter})
+	c.entries[key] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*limiterEntry).key)
+		}
+	}
+
+	return limiter
+}
+
+// TenantLimits is the requests-per-minute/burst pair enforced for one key.
+type TenantLimits struct {
+	RequestsPerMin int
+	BurstSize      int
+}
+
+// ParseTenantLimitOverrides parses a
+// "tenant:requests_per_min:burst_size,tenant:requests_per_min:burst_size"
+// string into a per-tenant TenantLimits map, ignoring malformed entries.
+func ParseTenantLimitOverrides(raw string) map[string]TenantLimits {
+	overrides := make(map[string]TenantLimits)
+	for _, triple := range strings.Split(raw, ",") {
+		triple = strings.TrimSpace(triple)
+		if triple == "" {
+			continue
+		}
+		parts := strings.Split(triple, ":")
+		if len(parts) != 3 {
+			continue
+		}
+		requestsPerMin, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		burstSize, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
+		}
+		overrides[parts[0]] = TenantLimits{RequestsPerMin: requestsPerMin, BurstSize: burstSize}
+	}
+	return overrides
+}
+
+// limiterBackend is the pluggable store behind RateLimitMiddleware: either
+// in-process memory or a shared Redis instance.
+type limiterBackend interface {
+	// allow reports whether a request for key is permitted under limits,
+	// how long the caller should wait before retrying if not, and how many
+	// tokens remain.
+	allow(ctx context.Context, key string, limits TenantLimits) (ok bool, retryAfter time.Duration, remaining int)
+}
+
+// memoryBackend keeps one rate.Limiter per key in a size-bounded LRU cache.
+// Limiter state is local to this process, so replicas don't share a budget.
+type memoryBackend struct {
+	cache *limiterLRU
+}
+
+func newMemoryBackend(capacity int) *memoryBackend {
+	return &memoryBackend{cache: newLimiterLRU(capacity)}
+}
+
+func (b *memoryBackend) allow(_ context.Context, key string, limits TenantLimits) (bool, time.Duration, int) {
+	limiter := b.cache.getOrCreate(key, func() *rate.Limiter {
+		return rate.NewLimiter(rate.Limit(float64(limits.RequestsPerMin)/60.0), limits.BurstSize)
+	})
+
+	if !limiter.Allow() {
+		reservation := limiter.Reserve()
+		retryAfter := reservation.Delay()
+		reservation.Cancel()
+		return false, retryAfter, 0
+	}
+	return true, 0, int(limiter.Tokens())
+}
+
+// tokenBucketScript atomically refills and debits a Redis-backed token
+// bucket so concurrent replicas share one budget per key. KEYS[1] is the
+// bucket's hash key; ARGV is capacity, refill rate in tokens/sec, and the
+// current unix time in (fractional) seconds.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local refill_rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call("HMGET", key, "tokens", "updated_at")
+local tokens = tonumber(bucket[1])
+local updated_at = tonumber(bucket[2])
+if tokens == nil then
+	tokens = capacity
+	updated_at = now
+end
+
+local elapsed = math.max(0, now - updated_at)
+tokens = math.min(capacity, tokens + elapsed * refill_rate)
+
+local allowed = 0
+if tokens >= 1 then
+	allowed = 1
+	tokens = tokens - 1
+end
+
+redis.call("HMSET", key, "tokens", tokens, "updated_at", now)
+redis.call("EXPIRE", key, 3600)
+return {allowed, tostring(tokens)}
+`)
+
+// redisBackend enforces the token bucket in Redis via tokenBucketScript, so
+// every replica of the service shares the same per-key budget. A Redis
+// outage fails open (allows the request) rather than taking ingestion down.
+type redisBackend struct {
+	client *redis.Client
+}
+
+func newRedisBackend(cfg *config.RateLimitConfig) *redisBackend {
+	return &redisBackend{client: redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})}
+}
+
+func (b *redisBackend) allow(ctx context.Context, key string, limits TenantLimits) (bool, time.Duration, int) {
+	refillPerSec := float64(limits.RequestsPerMin) / 60.0
+	now := float64(time.Now().UnixNano()) / 1e9
+
+	result, err := tokenBucketScript.Run(ctx, b.client, []string{"ratelimit:" + key},
+		limits.BurstSize, refillPerSec, now).Result()
+	if err != nil {
+		log.Printf("rate limit: redis backend unavailable, failing open: %v", err)
+		return true, 0, limits.BurstSize
+	}
+
+	values := result.([]interface{})
+	allowed := values[0].(int64) == 1
+	tokensLeft, _ := strconv.ParseFloat(values[1].(string), 64)
+	if !allowed {
+		retryAfter := time.Duration(float64(time.Second) / refillPerSec)
+		return false, retryAfter, 0
+	}
+	return true, 0, int(tokensLeft)
+}
+
+// RateLimitMiddleware enforces config.RateLimitConfig using a per-key
+// token-bucket limiter, with optional per-tenant overrides. Keys are
+// extracted by KeyFunc (defaults to client IP); bucket state lives in
+// whichever limiterBackend cfg.Backend selects.
+type RateLimitMiddleware struct {
+	cfg       *config.RateLimitConfig
+	keyFunc   KeyFunc
+	backend   limiterBackend
+	overrides map[string]TenantLimits
+}
+
+// NewRateLimitMiddleware builds a RateLimitMiddleware from cfg. If keyFunc
+// is nil, ClientIPKey is used.
+func NewRateLimitMiddleware(cfg *config.RateLimitConfig, keyFunc KeyFunc) *RateLimitMiddleware {
+	if keyFunc == nil {
+		keyFunc = ClientIPKey
+	}
+
+	var backend limiterBackend
+	if cfg.Backend == "redis" {
+		backend = newRedisBackend(cfg)
+	} else {
+		backend = newMemoryBackend(defaultLimiterCacheSize)
+	}
+
+	return &RateLimitMiddleware{
+		cfg:       cfg,
+		keyFunc:   keyFunc,
+		backend:   backend,
+		overrides: ParseTenantLimitOverrides(cfg.TenantOverrides),
+	}
+}
+
+func (m *RateLimitMiddleware) limitsFor(key string) TenantLimits {
+	if limits, ok := m.overrides[key]; ok {
+		return limits
+	}
+	return TenantLimits{RequestsPerMin: m.cfg.RequestsPerMin, BurstSize: m.cfg.BurstSize}
+}
+
+// Handler wraps next with rate limiting. When the configured limit is
+// exceeded it responds 429 with Retry-After, X-RateLimit-Remaining, and
+// X-RateLimit-Reset headers instead of calling next.
+func (m *RateLimitMiddleware) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.cfg.Enabled {
+			next(w, r)
+			return
+		}
+
+		key := m.keyFunc(r)
+		limits := m.limitsFor(key)
+		allowed, retryAfter, remaining := m.backend.allow(r.Context(), key, limits)
+
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(retryAfter).Unix(), 10))
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		next(w, r)
+	}
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import "strings"
+
+// TenantFromPath extracts the tenant ID from a /tenant/{tenant_id}/...
+// request path, the convention every tenant-scoped ingestion endpoint
+// follows. ok is false if path doesn't match that shape.
+func TenantFromPath(path string) (tenantID string, ok bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || parts[1] != "tenant" || parts[2] == "" {
+		return "", false
+	}
+	return parts[2], true
+}
@@ -2,11 +2,18 @@ package platform
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"ai-data-lake/ingestion/metrics"
 )
 
 type Client struct {
@@ -14,12 +21,46 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// endpointLabel normalizes urlPath into a low-cardinality metric label by
+// replacing the dynamic segment after "hash" (a schema hash) with ":hash",
+// e.g. "/api/schemas/hash/abc123/evolve" -> "/api/schemas/hash/:hash/evolve".
+func endpointLabel(urlPath string) string {
+	parts := strings.Split(urlPath, "/")
+	for i, part := range parts {
+		if part == "hash" && i+1 < len(parts) {
+			parts[i+1] = ":hash"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// metricsTransport records platform_api_seconds for every request made
+// through it, labeled by endpointLabel(req.URL.Path) and response status
+// code ("error" if the round trip itself failed).
+type metricsTransport struct {
+	next http.RoundTripper
+}
+
+func (t metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+
+	code := "error"
+	if resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	metrics.PlatformAPISeconds.WithLabelValues(endpointLabel(req.URL.Path), code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
 type SchemaResponse struct {
 	ID             int                      `json:"id"`
 	Hash           string                   `json:"hash"`
 	Name           string                   `json:"name"`
 	TenantID       string                   `json:"tenant_id"`
 	Status         string                   `json:"status"`
+	Version        int                      `json:"version"`
 	DetectedFields []map[string]interface{} `json:"detected_fields"`
 	ConfirmedAt    *time.Time               `json:"confirmed_at"`
 	CreatedAt      time.Time                `json:"created_at"`
@@ -41,20 +82,25 @@ type CreateSchemaResponse struct {
 	Message string `json:"message"`
 }
 
+// NewClient builds a Client whose requests are traced (OpenTelemetry spans,
+// parented by ctx when the call accepts one) and recorded under the
+// platform_api_seconds metric.
 func NewClient(baseURL string) *Client {
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: metricsTransport{next: otelhttp.NewTransport(http.DefaultTransport)},
 		},
 	}
 }
 
-// GetSchemaByHash retrieves a schema by hash and tenant
-func (c *Client) GetSchemaByHash(hash, tenantID string) (*SchemaResponse, error) {
+// GetSchemaByHash retrieves a schema by hash and tenant. ctx cancels the
+// request if the caller gives up waiting.
+func (c *Client) GetSchemaByHash(ctx context.Context, hash, tenantID string) (*SchemaResponse, error) {
 	url := fmt.Sprintf("%s/api/schemas/hash/%s", c.baseURL, hash)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -85,8 +131,9 @@ func (c *Client) GetSchemaByHash(hash, tenantID string) (*SchemaResponse, error)
 	return &schema, nil
 }
 
-// CreateSchema creates a new schema confirmation request
-func (c *Client) CreateSchema(req CreateSchemaRequest) (*CreateSchemaResponse, error) {
+// CreateSchema creates a new schema confirmation request. ctx cancels the
+// request if the caller gives up waiting.
+func (c *Client) CreateSchema(ctx context.Context, req CreateSchemaRequest) (*CreateSchemaResponse, error) {
 	url := fmt.Sprintf("%s/api/schemas", c.baseURL)
 
 	jsonData, err := json.Marshal(req)
@@ -94,7 +141,7 @@ func (c *Client) CreateSchema(req CreateSchemaRequest) (*CreateSchemaResponse, e
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -120,11 +167,116 @@ func (c *Client) CreateSchema(req CreateSchemaRequest) (*CreateSchemaResponse, e
 	return &createResp, nil
 }
 
-// IncrementPendingRecords increments the pending records count for a schema
-func (c *Client) IncrementPendingRecords(hash, tenantID string) error {
+// ListConfirmedSchemas retrieves every confirmed schema for tenantID, used
+// by the compatibility subsystem to diff a newly observed shape against
+// what's already live for that tenant. ctx cancels the request if the
+// caller gives up waiting.
+func (c *Client) ListConfirmedSchemas(ctx context.Context, tenantID string) ([]SchemaResponse, error) {
+	url := fmt.Sprintf("%s/api/schemas?tenant_id=%s&status=confirmed", c.baseURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Tenant-ID", tenantID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var schemas []SchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&schemas); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return schemas, nil
+}
+
+// EvolveSchema updates an existing confirmed schema's fields in place,
+// incrementing its version, and returns the updated record. ctx cancels the
+// request if the caller gives up waiting.
+func (c *Client) EvolveSchema(ctx context.Context, hash, tenantID string, fields []map[string]interface{}) (*SchemaResponse, error) {
+	url := fmt.Sprintf("%s/api/schemas/hash/%s/evolve", c.baseURL, hash)
+
+	jsonData, err := json.Marshal(map[string]interface{}{"detected_fields": fields})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Tenant-ID", tenantID)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var evolveResp SchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&evolveResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &evolveResp, nil
+}
+
+// GetSchemaVersions retrieves the version history for hash, oldest first.
+// ctx cancels the request if the caller gives up waiting.
+func (c *Client) GetSchemaVersions(ctx context.Context, hash, tenantID string) ([]SchemaResponse, error) {
+	url := fmt.Sprintf("%s/api/schemas/hash/%s/versions", c.baseURL, hash)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Tenant-ID", tenantID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, nil // Schema not found
+	}
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var versions []SchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return versions, nil
+}
+
+// IncrementPendingRecords increments the pending records count for a
+// schema. ctx cancels the request if the caller gives up waiting.
+func (c *Client) IncrementPendingRecords(ctx context.Context, hash, tenantID string) error {
 	url := fmt.Sprintf("%s/api/schemas/hash/%s/increment", c.baseURL, hash)
 
-	req, err := http.NewRequest("POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
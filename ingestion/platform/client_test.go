@@ -0,0 +1,28 @@
+package platform
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGetSchemaByHashRespectsContextCancellation verifies Client's requests
+// are bound to the caller's context, so a canceled ctx aborts the call
+// instead of the request running to completion regardless.
+func TestGetSchemaByHashRespectsContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetSchemaByHash(ctx, "abc123", "tenant-a"); err == nil {
+		t.Fatal("expected an error once ctx deadline exceeded, got nil")
+	}
+}
@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// avroType maps a detected Field.Type to an Avro primitive or logical type.
+func avroType(fieldType string) interface{} {
+	switch fieldType {
+	case "integer":
+		return "long"
+	case "float":
+		return "double"
+	case "boolean":
+		return "boolean"
+	case "datetime", "date", "timestamp":
+		return map[string]interface{}{"type": "long", "logicalType": "timestamp-millis"}
+	case "null":
+		return "null"
+	default:
+		// email, url, uuid, phone, json, string, array[*], object all fall
+		// back to a plain string representation.
+		return "string"
+	}
+}
+
+// BuildAvroSchema renders fields as an Avro record schema document named
+// name. Every field is made nullable (a ["null", T] union) unless marked
+// Required, matching how ingested data is only ever loosely structured.
+func BuildAvroSchema(name string, fields []Field) (string, error) {
+	avroFields := make([]map[string]interface{}, 0, len(fields))
+	for _, f := range fields {
+		fieldType := avroType(f.Type)
+		if !f.Required {
+			fieldType = []interface{}{"null", fieldType}
+		}
+		avroFields = append(avroFields, map[string]interface{}{
+			"name": avroFieldName(f.Name),
+			"type": fieldType,
+		})
+	}
+
+	doc := map[string]interface{}{
+		"type":   "record",
+		"name":   name,
+		"fields": avroFields,
+	}
+
+	encoded, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode Avro schema: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// avroFieldName replaces "." (used for nested field paths) with "_" since
+// Avro field names must be valid identifiers.
+func avroFieldName(name string) string {
+	out := make([]rune, 0, len(name))
+	for _, r := range name {
+		if r == '.' {
+			out = append(out, '_')
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
@@ -0,0 +1,253 @@
+package schema
+
+import "strings"
+
+// CompatibilityMode is the per-tenant policy for how aggressively a schema
+// change must be proven safe before it's allowed to evolve an existing
+// stream instead of queueing a brand new schema confirmation.
+type CompatibilityMode string
+
+const (
+	CompatibilityBackward CompatibilityMode = "backward"
+	CompatibilityForward  CompatibilityMode = "forward"
+	CompatibilityFull     CompatibilityMode = "full"
+	CompatibilityNone     CompatibilityMode = "none"
+)
+
+// ParseCompatibilityMode parses a compatibility_mode config value, defaulting
+// to CompatibilityNone (the original "every change is a new schema"
+// behavior) for anything unrecognized.
+func ParseCompatibilityMode(raw string) CompatibilityMode {
+	switch CompatibilityMode(raw) {
+	case CompatibilityBackward, CompatibilityForward, CompatibilityFull:
+		return CompatibilityMode(raw)
+	default:
+		return CompatibilityNone
+	}
+}
+
+// Allows reports whether a change classified as class is acceptable to
+// auto-evolve under this policy.
+func (m CompatibilityMode) Allows(class Classification) bool {
+	switch m {
+	case CompatibilityFull:
+		return class == ClassificationFull
+	case CompatibilityBackward:
+		return class == ClassificationFull || class == ClassificationBackward
+	case CompatibilityForward:
+		return class == ClassificationFull || class == ClassificationForward
+	default:
+		return false
+	}
+}
+
+// Classification is the result of diffing two field sets.
+type Classification string
+
+const (
+	// ClassificationFull means the new schema can read data written under
+	// the old schema AND the old schema can read data written under the new
+	// schema.
+	ClassificationFull Classification = "FULL"
+	// ClassificationBackward means consumers using the new schema can read
+	// data produced under the old schema (safe to upgrade readers first).
+	ClassificationBackward Classification = "BACKWARD"
+	// ClassificationForward means consumers still using the old schema can
+	// read data produced under the new schema (safe to upgrade writers
+	// first).
+	ClassificationForward Classification = "FORWARD"
+	// ClassificationBreaking means neither direction is safe; the change
+	// must go through the pending confirmation flow as a new schema.
+	ClassificationBreaking Classification = "BREAKING"
+)
+
+// FieldDiff describes how a field set changed relative to a prior schema.
+type FieldDiff struct {
+	Added              []Field
+	Removed            []Field
+	Widened            []Field // numeric type widened, e.g. integer -> float
+	NullabilityChanged []Field // Required flipped, by the new field's value
+}
+
+// numericWidth ranks numeric types from narrowest to widest so widening
+// (safe to read old data with a new, wider type) can be told apart from a
+// narrowing (unsafe).
+func numericWidth(t string) int {
+	switch t {
+	case "integer":
+		return 1
+	case "float":
+		return 2
+	default:
+		return 0
+	}
+}
+
+// DiffFields compares prior against next (both from DetectFields) and
+// buckets every change by name.
+func DiffFields(prior, next []Field) FieldDiff {
+	priorByName := make(map[string]Field, len(prior))
+	for _, f := range prior {
+		priorByName[f.Name] = f
+	}
+	nextByName := make(map[string]Field, len(next))
+	for _, f := range next {
+		nextByName[f.Name] = f
+	}
+
+	var diff FieldDiff
+	for _, f := range next {
+		old, existed := priorByName[f.Name]
+		if !existed {
+			diff.Added = append(diff.Added, f)
+			continue
+		}
+		if old.Required != f.Required {
+			diff.NullabilityChanged = append(diff.NullabilityChanged, f)
+		}
+		if old.Type != f.Type && numericWidth(f.Type) > numericWidth(old.Type) {
+			diff.Widened = append(diff.Widened, f)
+		}
+	}
+	for _, f := range prior {
+		if _, stillPresent := nextByName[f.Name]; !stillPresent {
+			diff.Removed = append(diff.Removed, f)
+		}
+	}
+	return diff
+}
+
+// Classify applies this package's compatibility rules to diff. The rules
+// are a deliberately simplified model of Avro/Confluent-style
+// compatibility, not a full implementation:
+//
+//   - Adding an optional field, or removing one, never breaks the direction
+//     that doesn't need it: added fields are ignored by old readers
+//     (forward-safe) but may be required by new readers of old data
+//     (backward-unsafe only if the added field is itself required).
+//   - Removing a required field means old readers expecting it can't read
+//     new data (forward-unsafe); removing an optional field is always safe.
+//   - Widening a numeric type (integer -> float) lets new readers parse old
+//     data (backward-safe) but old readers may not parse the wider new
+//     values correctly (forward-unsafe).
+//   - Relaxing a field from required to optional is always safe; tightening
+//     optional to required is backward-unsafe (old data may be missing it).
+func Classify(diff FieldDiff) Classification {
+	backwardSafe := true
+	forwardSafe := true
+
+	for _, f := range diff.Added {
+		if f.Required {
+			backwardSafe = false
+		}
+	}
+	for _, f := range diff.Removed {
+		if f.Required {
+			forwardSafe = false
+		}
+	}
+	for range diff.Widened {
+		forwardSafe = false
+	}
+	for _, f := range diff.NullabilityChanged {
+		if f.Required {
+			// optional -> required
+			backwardSafe = false
+		}
+	}
+
+	switch {
+	case backwardSafe && forwardSafe:
+		return ClassificationFull
+	case backwardSafe:
+		return ClassificationBackward
+	case forwardSafe:
+		return ClassificationForward
+	default:
+		return ClassificationBreaking
+	}
+}
+
+// PolicyConfig is the compatibility policy for every tenant: a default mode
+// plus explicit per-tenant overrides (compatibility_mode config).
+type PolicyConfig struct {
+	Default   CompatibilityMode
+	Overrides map[string]CompatibilityMode
+}
+
+// ModeFor returns tenantID's configured mode, falling back to the default
+// when it has no override.
+func (p PolicyConfig) ModeFor(tenantID string) CompatibilityMode {
+	if mode, ok := p.Overrides[tenantID]; ok {
+		return mode
+	}
+	return p.Default
+}
+
+// ParsePolicyConfig builds a PolicyConfig from a default mode and a
+// "tenant:mode,tenant:mode" overrides string, ignoring malformed pairs.
+func ParsePolicyConfig(defaultMode, overrides string) PolicyConfig {
+	policy := PolicyConfig{
+		Default:   ParseCompatibilityMode(defaultMode),
+		Overrides: make(map[string]CompatibilityMode),
+	}
+	for _, pair := range strings.Split(overrides, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tenantID, mode, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		policy.Overrides[tenantID] = ParseCompatibilityMode(mode)
+	}
+	return policy
+}
+
+// classRank orders classifications from least to most relaxed, so
+// DecideEvolution can prefer a FULL match over a merely BACKWARD/FORWARD
+// one when several confirmed schemas would qualify.
+func classRank(c Classification) int {
+	switch c {
+	case ClassificationFull:
+		return 2
+	case ClassificationBackward, ClassificationForward:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EvolutionDecision is the result of comparing a newly observed field set
+// against a tenant's confirmed schemas.
+type EvolutionDecision struct {
+	Evolve bool
+	Target *Record
+	Class  Classification
+}
+
+// DecideEvolution diffs fields against every confirmed Record in existing
+// and picks the most relaxed match that mode allows to auto-evolve. If none
+// qualify, the caller should fall back to the pending-confirmation flow for
+// a brand new schema.
+func DecideEvolution(fields []Field, existing []Record, mode CompatibilityMode) EvolutionDecision {
+	var best *Record
+	var bestClass Classification
+
+	for i := range existing {
+		class := Classify(DiffFields(existing[i].Fields, fields))
+		if !mode.Allows(class) {
+			continue
+		}
+		if best == nil || classRank(class) > classRank(bestClass) {
+			best = &existing[i]
+			bestClass = class
+		}
+	}
+
+	if best == nil {
+		return EvolutionDecision{}
+	}
+	return EvolutionDecision{Evolve: true, Target: best, Class: bestClass}
+}
@@ -0,0 +1,107 @@
+package schema
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		diff FieldDiff
+		want Classification
+	}{
+		{
+			name: "no changes",
+			diff: FieldDiff{},
+			want: ClassificationFull,
+		},
+		{
+			name: "optional field added",
+			diff: FieldDiff{Added: []Field{{Name: "note", Required: false}}},
+			want: ClassificationFull,
+		},
+		{
+			name: "required field added",
+			diff: FieldDiff{Added: []Field{{Name: "note", Required: true}}},
+			want: ClassificationForward,
+		},
+		{
+			name: "optional field removed",
+			diff: FieldDiff{Removed: []Field{{Name: "note", Required: false}}},
+			want: ClassificationFull,
+		},
+		{
+			name: "required field removed",
+			diff: FieldDiff{Removed: []Field{{Name: "note", Required: true}}},
+			want: ClassificationBackward,
+		},
+		{
+			name: "numeric type widened",
+			diff: FieldDiff{Widened: []Field{{Name: "amount", Type: "float"}}},
+			want: ClassificationBackward,
+		},
+		{
+			name: "field relaxed from required to optional",
+			diff: FieldDiff{NullabilityChanged: []Field{{Name: "note", Required: false}}},
+			want: ClassificationFull,
+		},
+		{
+			name: "field tightened from optional to required",
+			diff: FieldDiff{NullabilityChanged: []Field{{Name: "note", Required: true}}},
+			want: ClassificationForward,
+		},
+		{
+			name: "required field added and removed",
+			diff: FieldDiff{
+				Added:   []Field{{Name: "note", Required: true}},
+				Removed: []Field{{Name: "legacy", Required: true}},
+			},
+			want: ClassificationBreaking,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.diff); got != tt.want {
+				t.Errorf("Classify(%+v) = %s, want %s", tt.diff, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecideEvolution(t *testing.T) {
+	existing := []Record{
+		{Hash: "old", Fields: []Field{{Name: "id", Type: "integer", Required: true}}},
+	}
+
+	t.Run("compatible change under backward mode evolves", func(t *testing.T) {
+		fields := []Field{
+			{Name: "id", Type: "integer", Required: true},
+			{Name: "note", Type: "string", Required: false},
+		}
+		decision := DecideEvolution(fields, existing, CompatibilityBackward)
+		if !decision.Evolve {
+			t.Fatal("expected Evolve = true for an optional field addition under backward mode")
+		}
+		if decision.Target.Hash != "old" {
+			t.Errorf("Target.Hash = %q, want %q", decision.Target.Hash, "old")
+		}
+	})
+
+	t.Run("breaking change never evolves", func(t *testing.T) {
+		fields := []Field{{Name: "note", Type: "string", Required: true}} // adds required "note", drops required "id"
+		decision := DecideEvolution(fields, existing, CompatibilityFull)
+		if decision.Evolve {
+			t.Fatal("expected Evolve = false for a breaking change")
+		}
+	})
+
+	t.Run("compatible change rejected under none mode", func(t *testing.T) {
+		fields := []Field{
+			{Name: "id", Type: "integer", Required: true},
+			{Name: "note", Type: "string", Required: false},
+		}
+		decision := DecideEvolution(fields, existing, CompatibilityNone)
+		if decision.Evolve {
+			t.Fatal("expected Evolve = false under CompatibilityNone, which allows no auto-evolution")
+		}
+	})
+}
@@ -0,0 +1,324 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// confluentMagicByte prefixes every Confluent wire-format payload.
+const confluentMagicByte = 0x0
+
+// ConfluentRegistry is a Registry backed by a Confluent-compatible Schema
+// Registry (subjects named "<topic>-value"). It registers Avro schemas and
+// produces/reads the standard magic-byte + 4-byte-schema-ID wire format.
+type ConfluentRegistry struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewConfluentRegistry builds a ConfluentRegistry against baseURL (e.g.
+// http://schema-registry:8081).
+func NewConfluentRegistry(baseURL string) *ConfluentRegistry {
+	return &ConfluentRegistry{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (r *ConfluentRegistry) subject(hash string) string {
+	return fmt.Sprintf("schema-%s-value", hash)
+}
+
+func (r *ConfluentRegistry) Detect(data map[string]interface{}) []Field {
+	return DetectFields(data, "")
+}
+
+func (r *ConfluentRegistry) Fingerprint(fields []Field) string {
+	return Fingerprint(fields)
+}
+
+type confluentVersionResponse struct {
+	ID      int32  `json:"id"`
+	Version int    `json:"version"`
+	Schema  string `json:"schema"`
+}
+
+// Lookup fetches the latest registered version for hash's subject. It
+// returns nil (not an error) when the subject has never been registered.
+// ctx cancels the request if the caller gives up waiting.
+func (r *ConfluentRegistry) Lookup(ctx context.Context, hash, tenantID string) (*Record, error) {
+	url := fmt.Sprintf("%s/subjects/%s/versions/latest", r.baseURL, r.subject(hash))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var version confluentVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+
+	return &Record{
+		Hash:       hash,
+		TenantID:   tenantID,
+		Status:     "confirmed", // presence in the registry implies it's live
+		Version:    version.Version,
+		AvroSchema: version.Schema,
+		SchemaID:   version.ID,
+	}, nil
+}
+
+type confluentRegisterRequest struct {
+	Schema string `json:"schema"`
+}
+
+type confluentRegisterResponse struct {
+	ID int32 `json:"id"`
+}
+
+// Register computes req's Avro schema and registers it under the
+// schema-<hash>-value subject, returning a confirmed Record. ctx cancels
+// the request if the caller gives up waiting.
+func (r *ConfluentRegistry) Register(ctx context.Context, req RegisterRequest) (*Record, error) {
+	return r.registerUnder(ctx, Fingerprint(req.Fields), req)
+}
+
+// registerUnder registers req's Avro schema under hash's subject.
+func (r *ConfluentRegistry) registerUnder(ctx context.Context, hash string, req RegisterRequest) (*Record, error) {
+	avroDoc, err := BuildAvroSchema(fmt.Sprintf("schema_%s", hash), req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(confluentRegisterRequest{Schema: avroDoc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal registration request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/subjects/%s/versions", r.baseURL, r.subject(hash))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	httpResp, err := r.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register schema: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(httpResp.Body)
+		return nil, fmt.Errorf("schema registry returned %d: %s", httpResp.StatusCode, string(respBody))
+	}
+
+	var registerResp confluentRegisterResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&registerResp); err != nil {
+		return nil, fmt.Errorf("failed to decode registration response: %w", err)
+	}
+
+	jsonDoc, err := BuildJSONSchema(req.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Hash:       hash,
+		TenantID:   req.TenantID,
+		Status:     "confirmed",
+		Fields:     req.Fields,
+		AvroSchema: avroDoc,
+		JSONSchema: jsonDoc,
+		SchemaID:   registerResp.ID,
+	}, nil
+}
+
+// Evolve registers a new Avro version for the same subject; the Confluent
+// Schema Registry rejects it if the version isn't compatible with its
+// configured compatibility level for the subject.
+func (r *ConfluentRegistry) Evolve(ctx context.Context, hash, tenantID string, fields []Field) (*Record, error) {
+	return r.registerUnder(ctx, hash, RegisterRequest{TenantID: tenantID, Fields: fields})
+}
+
+// ConfirmedForTenant is not supported: Confluent subjects are named only
+// after the schema hash ("schema-<hash>-value"), with no tenant scoping, so
+// there's no way to list "this tenant's" schemas. Compatibility checking
+// against this backend needs a tenant-aware Registry (e.g. PlatformRegistry
+// or FileRegistry) instead. ctx is accepted to satisfy Registry but unused.
+func (r *ConfluentRegistry) ConfirmedForTenant(_ context.Context, tenantID string) ([]Record, error) {
+	return nil, fmt.Errorf("confluent registry does not support listing schemas by tenant")
+}
+
+// Versions lists every version registered for hash's subject, oldest
+// first. ctx cancels the request if the caller gives up waiting.
+func (r *ConfluentRegistry) Versions(ctx context.Context, hash, tenantID string) ([]Record, error) {
+	url := fmt.Sprintf("%s/subjects/%s/versions", r.baseURL, r.subject(hash))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var versionNumbers []int
+	if err := json.NewDecoder(resp.Body).Decode(&versionNumbers); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+
+	records := make([]Record, 0, len(versionNumbers))
+	for _, v := range versionNumbers {
+		record, err := r.fetchVersion(ctx, hash, tenantID, v)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+	return records, nil
+}
+
+func (r *ConfluentRegistry) fetchVersion(ctx context.Context, hash, tenantID string, version int) (*Record, error) {
+	url := fmt.Sprintf("%s/subjects/%s/versions/%d", r.baseURL, r.subject(hash), version)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("schema registry returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var decoded confluentVersionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %w", err)
+	}
+
+	return &Record{
+		Hash:       hash,
+		TenantID:   tenantID,
+		Status:     "confirmed",
+		Version:    decoded.Version,
+		AvroSchema: decoded.Schema,
+		SchemaID:   decoded.ID,
+	}, nil
+}
+
+// EncodeWireFormat prepends the Confluent wire-format header (magic byte +
+// big-endian 4-byte schema ID) to payload.
+func EncodeWireFormat(schemaID int32, payload []byte) []byte {
+	out := make([]byte, 5+len(payload))
+	out[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(out[1:5], uint32(schemaID))
+	copy(out[5:], payload)
+	return out
+}
+
+// DecodeWireFormat strips the Confluent wire-format header from encoded,
+// returning the schema ID and the remaining payload.
+func DecodeWireFormat(encoded []byte) (schemaID int32, payload []byte, err error) {
+	if len(encoded) < 5 {
+		return 0, nil, fmt.Errorf("message too short to contain a wire-format header")
+	}
+	if encoded[0] != confluentMagicByte {
+		return 0, nil, fmt.Errorf("unexpected magic byte %#x", encoded[0])
+	}
+	schemaID = int32(binary.BigEndian.Uint32(encoded[1:5]))
+	return schemaID, encoded[5:], nil
+}
+
+// ConfluentSerializer adapts ConfluentRegistry to kafka.Serializer (matched
+// structurally - this package doesn't import kafka): Encode wraps a
+// producer's JSON payload with EncodeWireFormat, looking up and caching
+// hash's Confluent schema ID on first use, and Decode strips that header
+// back off via DecodeWireFormat for the consumer side.
+type ConfluentSerializer struct {
+	registry *ConfluentRegistry
+
+	mu  sync.Mutex
+	ids map[string]int32 // schema hash -> Confluent schema ID
+}
+
+// NewConfluentSerializer builds a ConfluentSerializer on top of registry.
+func NewConfluentSerializer(registry *ConfluentRegistry) *ConfluentSerializer {
+	return &ConfluentSerializer{registry: registry, ids: make(map[string]int32)}
+}
+
+// Encode wraps payload with the Confluent wire-format header for hash's
+// schema ID, looking it up from the registry (and caching the result) if
+// it isn't already known.
+func (s *ConfluentSerializer) Encode(ctx context.Context, hash string, payload []byte) ([]byte, error) {
+	id, err := s.schemaID(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeWireFormat(id, payload), nil
+}
+
+// Decode strips the Confluent wire-format header from payload, discarding
+// the schema ID it carries (the consumer trusts the JSON body's own
+// schema_hash field rather than resolving it back to a subject/version).
+func (s *ConfluentSerializer) Decode(payload []byte) ([]byte, error) {
+	_, body, err := DecodeWireFormat(payload)
+	return body, err
+}
+
+func (s *ConfluentSerializer) schemaID(ctx context.Context, hash string) (int32, error) {
+	s.mu.Lock()
+	id, ok := s.ids[hash]
+	s.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	record, err := s.registry.Lookup(ctx, hash, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up Confluent schema ID for hash %s: %w", hash, err)
+	}
+	if record == nil {
+		return 0, fmt.Errorf("no Confluent schema registered for hash %s", hash)
+	}
+
+	s.mu.Lock()
+	s.ids[hash] = record.SchemaID
+	s.mu.Unlock()
+	return record.SchemaID, nil
+}
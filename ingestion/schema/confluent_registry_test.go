@@ -0,0 +1,51 @@
+package schema
+
+import "testing"
+
+// TestEncodeDecodeWireFormatRoundTrips verifies DecodeWireFormat recovers
+// exactly the schema ID and payload EncodeWireFormat was given.
+func TestEncodeDecodeWireFormatRoundTrips(t *testing.T) {
+	encoded := EncodeWireFormat(42, []byte(`{"hello":"world"}`))
+
+	schemaID, payload, err := DecodeWireFormat(encoded)
+	if err != nil {
+		t.Fatalf("DecodeWireFormat: %v", err)
+	}
+	if schemaID != 42 {
+		t.Errorf("schemaID = %d, want 42", schemaID)
+	}
+	if string(payload) != `{"hello":"world"}` {
+		t.Errorf("payload = %q, want %q", payload, `{"hello":"world"}`)
+	}
+}
+
+// TestDecodeWireFormatRejectsBadMagicByte verifies DecodeWireFormat refuses
+// a payload that doesn't start with the Confluent magic byte, rather than
+// silently misinterpreting arbitrary bytes as a schema ID.
+func TestDecodeWireFormatRejectsBadMagicByte(t *testing.T) {
+	bad := append([]byte{0x1}, EncodeWireFormat(1, []byte("x"))[1:]...)
+	if _, _, err := DecodeWireFormat(bad); err == nil {
+		t.Fatal("expected an error for an unexpected magic byte, got nil")
+	}
+}
+
+// TestConfluentSerializerEncodeDecodeRoundTrips verifies
+// ConfluentSerializer.Encode/Decode agree on the wire format and that the
+// schema ID used by Encode is cached rather than re-looked-up.
+func TestConfluentSerializerEncodeDecodeRoundTrips(t *testing.T) {
+	s := NewConfluentSerializer(nil)
+	s.ids["abc123"] = 7 // seed the cache so Encode never has to call the (nil) registry
+
+	wire, err := s.Encode(nil, "abc123", []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	decoded, err := s.Decode(wire)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if string(decoded) != `{"a":1}` {
+		t.Errorf("decoded = %q, want %q", decoded, `{"a":1}`)
+	}
+}
@@ -0,0 +1,295 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field describes one detected field of an ingested record: its type,
+// a sample value, and enough metadata (format, precision, observed range)
+// to build an Avro or JSON-Schema document from it.
+type Field struct {
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	SampleValue interface{}            `json:"sample_value"`
+	Required    bool                   `json:"required"`
+	Format      string                 `json:"format,omitempty"`      // e.g., "YYYY-MM-DD", "###.##"
+	Pattern     string                 `json:"pattern,omitempty"`     // regex pattern
+	Precision   int                    `json:"precision,omitempty"`   // decimal places
+	Scale       int                    `json:"scale,omitempty"`       // total digits
+	MinValue    *float64               `json:"min_value,omitempty"`   // min observed value
+	MaxValue    *float64               `json:"max_value,omitempty"`   // max observed value
+	Constraints map[string]interface{} `json:"constraints,omitempty"` // additional constraints
+}
+
+// DetectFields analyzes data and detects field types with metadata. It is
+// the same traversal used to build the flat hash fingerprint, the Avro
+// schema, and the JSON-Schema document, so all three always agree on shape.
+func DetectFields(data map[string]interface{}, prefix string) []Field {
+	var fields []Field
+
+	for key, value := range data {
+		fieldName := key
+		if prefix != "" {
+			fieldName = fmt.Sprintf("%s.%s", prefix, key)
+		}
+
+		if nestedMap, ok := value.(map[string]interface{}); ok {
+			// Recursively detect nested fields
+			nestedFields := DetectFields(nestedMap, fieldName)
+			fields = append(fields, nestedFields...)
+		} else {
+			// Detect field type with metadata
+			fieldType, format, precision, minVal, maxVal := detectFieldType(value)
+
+			field := Field{
+				Name:        fieldName,
+				Type:        fieldType,
+				SampleValue: value,
+				Required:    value != nil, // Required if not null
+				Format:      format,
+				Precision:   precision,
+				MinValue:    minVal,
+				MaxValue:    maxVal,
+			}
+
+			// Add scale for numeric types (total number of digits)
+			if precision > 0 && (fieldType == "float" || fieldType == "integer") {
+				if minVal != nil {
+					totalDigits := len(fmt.Sprintf("%.0f", *minVal))
+					field.Scale = totalDigits + precision
+				}
+			}
+
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// detectFieldType returns the type and metadata for a field value
+func detectFieldType(value interface{}) (fieldType string, format string, precision int, minVal *float64, maxVal *float64) {
+	if value == nil {
+		return "null", "", 0, nil, nil
+	}
+
+	switch v := value.(type) {
+	case bool:
+		return "boolean", "", 0, nil, nil
+	case int:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case int8:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case int16:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case int32:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case int64:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case uint:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case uint8:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case uint16:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case uint32:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case uint64:
+		val := float64(v)
+		return "integer", "", 0, &val, &val
+	case float32:
+		floatVal := float64(v)
+		prec := detectFloatPrecision(floatVal)
+		return "float", "", prec, &floatVal, &floatVal
+	case float64:
+		prec := detectFloatPrecision(v)
+		return "float", "", prec, &v, &v
+	case string:
+		strType, strFormat := detectStringType(v)
+		return strType, strFormat, 0, nil, nil
+	case []interface{}:
+		arrayType := detectArrayType(v)
+		return arrayType, "", 0, nil, nil
+	case map[string]interface{}:
+		return "object", "", 0, nil, nil
+	default:
+		return "string", "", 0, nil, nil
+	}
+}
+
+// detectFloatPrecision returns the number of decimal places in a float
+func detectFloatPrecision(f float64) int {
+	s := fmt.Sprintf("%f", f)
+	// Trim trailing zeros
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimRight(s, ".")
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 2 {
+		return len(parts[1])
+	}
+	return 0
+}
+
+func detectArrayType(arr []interface{}) string {
+	if len(arr) == 0 {
+		return "array[empty]"
+	}
+
+	// Analyze first few elements to determine array type
+	sampleSize := len(arr)
+	if sampleSize > 3 {
+		sampleSize = 3
+	}
+
+	typeMap := make(map[string]int)
+	for i := 0; i < sampleSize; i++ {
+		elementType, _, _, _, _ := detectFieldType(arr[i])
+		typeMap[elementType]++
+	}
+
+	// Find the most common type
+	var dominantType string
+	maxCount := 0
+	for t, count := range typeMap {
+		if count > maxCount {
+			maxCount = count
+			dominantType = t
+		}
+	}
+
+	// Return array type with element information
+	if len(typeMap) == 1 {
+		return fmt.Sprintf("array[%s]", dominantType)
+	} else {
+		return fmt.Sprintf("array[mixed:%s]", dominantType)
+	}
+}
+
+// detectStringType determines the specific type of string and returns type and format
+func detectStringType(s string) (string, string) {
+	// Email detection
+	if strings.Contains(s, "@") && strings.Contains(s, ".") {
+		return "email", ""
+	}
+
+	// URL detection
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return "url", ""
+	}
+
+	// UUID detection (basic pattern)
+	if len(s) == 36 && strings.Count(s, "-") == 4 {
+		return "uuid", ""
+	}
+
+	// Date/DateTime detection with format capture
+	if dateType, format := detectDateFormat(s); dateType != "" {
+		return dateType, format
+	}
+
+	// JSON detection
+	if (strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")) ||
+		(strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]")) {
+		return "json", ""
+	}
+
+	// Phone number detection (basic)
+	if len(s) >= 10 && strings.ContainsAny(s, "0123456789") {
+		digitCount := 0
+		for _, r := range s {
+			if r >= '0' && r <= '9' {
+				digitCount++
+			}
+		}
+		if digitCount >= 10 && digitCount <= 15 {
+			return "phone", ""
+		}
+	}
+
+	return "string", ""
+}
+
+// detectDateFormat detects date/datetime formats and returns the type and format string
+func detectDateFormat(s string) (string, string) {
+	if len(s) < 8 {
+		return "", ""
+	}
+
+	// ISO 8601 datetime with timezone (YYYY-MM-DDTHH:mm:ssZ or variations)
+	if strings.Contains(s, "T") {
+		if strings.HasSuffix(s, "Z") {
+			return "datetime", "ISO8601-UTC"
+		}
+		if strings.Contains(s, "+") || strings.LastIndex(s, "-") > 10 {
+			return "datetime", "ISO8601-TZ"
+		}
+		if len(s) >= 19 { // YYYY-MM-DDTHH:mm:ss
+			return "datetime", "ISO8601"
+		}
+	}
+
+	// Common date patterns
+	if len(s) == 10 {
+		// YYYY-MM-DD
+		if s[4] == '-' && s[7] == '-' {
+			return "date", "YYYY-MM-DD"
+		}
+		// DD/MM/YYYY or MM/DD/YYYY
+		if s[2] == '/' && s[5] == '/' {
+			return "date", "DD/MM/YYYY"
+		}
+		// DD-MM-YYYY
+		if s[2] == '-' && s[5] == '-' {
+			return "date", "DD-MM-YYYY"
+		}
+		// YYYY/MM/DD
+		if s[4] == '/' && s[7] == '/' {
+			return "date", "YYYY/MM/DD"
+		}
+	}
+
+	// DateTime with space separator (YYYY-MM-DD HH:mm:ss)
+	if len(s) == 19 && s[4] == '-' && s[7] == '-' && s[10] == ' ' && s[13] == ':' && s[16] == ':' {
+		return "datetime", "YYYY-MM-DD HH:mm:ss"
+	}
+
+	// DateTime with T separator but no timezone (YYYY-MM-DDTHH:mm:ss)
+	if len(s) == 19 && s[4] == '-' && s[7] == '-' && s[10] == 'T' && s[13] == ':' && s[16] == ':' {
+		return "datetime", "YYYY-MM-DDTHH:mm:ss"
+	}
+
+	// DD/MM/YYYY HH:mm:ss
+	if len(s) == 19 && s[2] == '/' && s[5] == '/' && s[10] == ' ' && s[13] == ':' && s[16] == ':' {
+		return "datetime", "DD/MM/YYYY HH:mm:ss"
+	}
+
+	// Unix timestamp (10 digits for seconds, 13 for milliseconds)
+	if len(s) == 10 || len(s) == 13 {
+		allDigits := true
+		for _, r := range s {
+			if r < '0' || r > '9' {
+				allDigits = false
+				break
+			}
+		}
+		if allDigits {
+			if len(s) == 10 {
+				return "timestamp", "unix-seconds"
+			}
+			return "timestamp", "unix-milliseconds"
+		}
+	}
+
+	return "", ""
+}
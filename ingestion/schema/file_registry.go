@@ -0,0 +1,171 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileRegistry is a Registry backed by JSON files on the local filesystem,
+// one per tenant/hash holding that logical schema's full version history
+// (oldest first). It requires no network services and is meant for local
+// development and tests.
+type FileRegistry struct {
+	dir string
+}
+
+// NewFileRegistry builds a FileRegistry rooted at dir, creating it if
+// necessary.
+func NewFileRegistry(dir string) (*FileRegistry, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create schema registry dir: %w", err)
+	}
+	return &FileRegistry{dir: dir}, nil
+}
+
+func (r *FileRegistry) tenantDir(tenantID string) string {
+	return filepath.Join(r.dir, fmt.Sprintf("tenant_%s", tenantID))
+}
+
+func (r *FileRegistry) path(hash, tenantID string) string {
+	return filepath.Join(r.tenantDir(tenantID), hash+".json")
+}
+
+func (r *FileRegistry) Detect(data map[string]interface{}) []Field {
+	return DetectFields(data, "")
+}
+
+func (r *FileRegistry) Fingerprint(fields []Field) string {
+	return Fingerprint(fields)
+}
+
+// readVersions reads hash's full version history (oldest first), or nil if
+// it's never been registered.
+func (r *FileRegistry) readVersions(hash, tenantID string) ([]Record, error) {
+	raw, err := os.ReadFile(r.path(hash, tenantID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	var versions []Record
+	if err := json.Unmarshal(raw, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode schema file: %w", err)
+	}
+	return versions, nil
+}
+
+// Lookup returns the latest version of hash's Record. ctx is accepted to
+// satisfy Registry but unused: this backend is local filesystem I/O only.
+func (r *FileRegistry) Lookup(_ context.Context, hash, tenantID string) (*Record, error) {
+	versions, err := r.readVersions(hash, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	latest := versions[len(versions)-1]
+	return &latest, nil
+}
+
+func (r *FileRegistry) Register(_ context.Context, req RegisterRequest) (*Record, error) {
+	hash := Fingerprint(req.Fields)
+	return r.appendVersion(hash, &Record{
+		Hash:     hash,
+		TenantID: req.TenantID,
+		Status:   "confirmed", // dev registry: no confirmation workflow
+		Version:  1,
+		Fields:   req.Fields,
+	})
+}
+
+func (r *FileRegistry) Evolve(_ context.Context, hash, tenantID string, fields []Field) (*Record, error) {
+	versions, err := r.readVersions(hash, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	nextVersion := 1
+	if len(versions) > 0 {
+		nextVersion = versions[len(versions)-1].Version + 1
+	}
+	return r.appendVersion(hash, &Record{
+		Hash:     hash,
+		TenantID: tenantID,
+		Status:   "confirmed",
+		Version:  nextVersion,
+		Fields:   fields,
+	})
+}
+
+// ConfirmedForTenant returns the latest confirmed version of every logical
+// schema registered for tenantID.
+func (r *FileRegistry) ConfirmedForTenant(ctx context.Context, tenantID string) ([]Record, error) {
+	entries, err := os.ReadDir(r.tenantDir(tenantID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant schema dir: %w", err)
+	}
+
+	var records []Record
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		hash := strings.TrimSuffix(entry.Name(), ".json")
+		record, err := r.Lookup(ctx, hash, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if record != nil && record.Status == "confirmed" {
+			records = append(records, *record)
+		}
+	}
+	return records, nil
+}
+
+// Versions returns hash's full version history, oldest first. ctx is
+// accepted to satisfy Registry but unused.
+func (r *FileRegistry) Versions(_ context.Context, hash, tenantID string) ([]Record, error) {
+	return r.readVersions(hash, tenantID)
+}
+
+func (r *FileRegistry) appendVersion(hash string, record *Record) (*Record, error) {
+	avroDoc, err := BuildAvroSchema(fmt.Sprintf("schema_%s", hash), record.Fields)
+	if err != nil {
+		return nil, err
+	}
+	jsonDoc, err := BuildJSONSchema(record.Fields)
+	if err != nil {
+		return nil, err
+	}
+	record.AvroSchema = avroDoc
+	record.JSONSchema = jsonDoc
+
+	versions, err := r.readVersions(hash, record.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, *record)
+
+	path := r.path(hash, record.TenantID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tenant dir: %w", err)
+	}
+
+	encoded, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema record: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write schema file: %w", err)
+	}
+	return record, nil
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonSchemaType maps a detected Field.Type to a JSON-Schema "type" value.
+func jsonSchemaType(fieldType string) string {
+	switch fieldType {
+	case "integer":
+		return "integer"
+	case "float":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	default:
+		// email, url, uuid, phone, datetime, date, timestamp, json, string,
+		// array[*], object all fall back to "string" at this flat level.
+		// BuildJSONSchema carries the detector's Format (e.g. a date layout)
+		// in "format" when one was recorded; the detected type itself is
+		// not kept.
+		return "string"
+	}
+}
+
+// BuildJSONSchema renders fields as a draft-07 JSON Schema document.
+func BuildJSONSchema(fields []Field) (string, error) {
+	properties := make(map[string]interface{}, len(fields))
+	var required []string
+
+	for _, f := range fields {
+		prop := map[string]interface{}{
+			"type": jsonSchemaType(f.Type),
+		}
+		if f.Format != "" {
+			prop["format"] = f.Format
+		}
+		properties[f.Name] = prop
+
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	doc := map[string]interface{}{
+		"$schema":    "http://json-schema.org/draft-07/schema#",
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		doc["required"] = required
+	}
+
+	encoded, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON Schema: %w", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"ai-data-lake/ingestion/platform"
+)
+
+// PlatformRegistry is a Registry backed by the proprietary platform HTTP
+// API. This is the original (and still default) backend: schema
+// confirmation is a human/workflow decision recorded in the platform
+// service, not the registry itself.
+type PlatformRegistry struct {
+	client *platform.Client
+}
+
+// NewPlatformRegistry builds a PlatformRegistry on top of client.
+func NewPlatformRegistry(client *platform.Client) *PlatformRegistry {
+	return &PlatformRegistry{client: client}
+}
+
+func (r *PlatformRegistry) Detect(data map[string]interface{}) []Field {
+	return DetectFields(data, "")
+}
+
+func (r *PlatformRegistry) Fingerprint(fields []Field) string {
+	return Fingerprint(fields)
+}
+
+func (r *PlatformRegistry) Lookup(ctx context.Context, hash, tenantID string) (*Record, error) {
+	resp, err := r.client.GetSchemaByHash(ctx, hash, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+
+	avroDoc, jsonDoc, err := r.documentsForResponse(hash, resp.DetectedFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Hash:       resp.Hash,
+		TenantID:   resp.TenantID,
+		Status:     resp.Status,
+		Version:    resp.Version,
+		Fields:     fieldsFromPlatform(resp.DetectedFields),
+		AvroSchema: avroDoc,
+		JSONSchema: jsonDoc,
+	}, nil
+}
+
+func (r *PlatformRegistry) Register(ctx context.Context, req RegisterRequest) (*Record, error) {
+	hash := Fingerprint(req.Fields)
+	kafkaTopic := fmt.Sprintf("schema-%s", hash)
+
+	platformFields := make([]map[string]interface{}, 0, len(req.Fields))
+	for _, field := range req.Fields {
+		platformFields = append(platformFields, map[string]interface{}{
+			"name":         field.Name,
+			"type":         field.Type,
+			"sample_value": field.SampleValue,
+			"required":     field.Required,
+		})
+	}
+
+	createResp, err := r.client.CreateSchema(ctx, platform.CreateSchemaRequest{
+		Hash:           hash,
+		KafkaTopic:     kafkaTopic,
+		TenantID:       req.TenantID,
+		SampleData:     req.SampleData,
+		DetectedFields: platformFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	avroDoc, jsonDoc, err := r.documentsForResponse(hash, platformFields)
+	if err != nil {
+		return nil, err
+	}
+
+	status := "pending"
+	if createResp != nil {
+		status = createResp.Status
+	}
+
+	return &Record{
+		Hash:       hash,
+		TenantID:   req.TenantID,
+		Status:     status,
+		Version:    1,
+		Fields:     req.Fields,
+		AvroSchema: avroDoc,
+		JSONSchema: jsonDoc,
+	}, nil
+}
+
+// Evolve updates the platform record for hash in place under fields,
+// incrementing its version, instead of creating a new schema/topic pair.
+func (r *PlatformRegistry) Evolve(ctx context.Context, hash, tenantID string, fields []Field) (*Record, error) {
+	platformFields := make([]map[string]interface{}, 0, len(fields))
+	for _, field := range fields {
+		platformFields = append(platformFields, map[string]interface{}{
+			"name":         field.Name,
+			"type":         field.Type,
+			"sample_value": field.SampleValue,
+			"required":     field.Required,
+		})
+	}
+
+	resp, err := r.client.EvolveSchema(ctx, hash, tenantID, platformFields)
+	if err != nil {
+		return nil, err
+	}
+
+	avroDoc, jsonDoc, err := r.documentsForResponse(hash, platformFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Record{
+		Hash:       hash,
+		TenantID:   tenantID,
+		Status:     resp.Status,
+		Version:    resp.Version,
+		Fields:     fields,
+		AvroSchema: avroDoc,
+		JSONSchema: jsonDoc,
+	}, nil
+}
+
+// ConfirmedForTenant lists every confirmed schema the platform API knows
+// about for tenantID.
+func (r *PlatformRegistry) ConfirmedForTenant(ctx context.Context, tenantID string) ([]Record, error) {
+	resps, err := r.client.ListConfirmedSchemas(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(resps))
+	for _, resp := range resps {
+		records = append(records, Record{
+			Hash:     resp.Hash,
+			TenantID: resp.TenantID,
+			Status:   resp.Status,
+			Version:  resp.Version,
+			Fields:   fieldsFromPlatform(resp.DetectedFields),
+		})
+	}
+	return records, nil
+}
+
+// Versions lists hash's version history, oldest first.
+func (r *PlatformRegistry) Versions(ctx context.Context, hash, tenantID string) ([]Record, error) {
+	resps, err := r.client.GetSchemaVersions(ctx, hash, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(resps))
+	for _, resp := range resps {
+		records = append(records, Record{
+			Hash:     resp.Hash,
+			TenantID: resp.TenantID,
+			Status:   resp.Status,
+			Version:  resp.Version,
+			Fields:   fieldsFromPlatform(resp.DetectedFields),
+		})
+	}
+	return records, nil
+}
+
+func (r *PlatformRegistry) documentsForResponse(hash string, detectedFields []map[string]interface{}) (avroDoc, jsonDoc string, err error) {
+	fields := fieldsFromPlatform(detectedFields)
+	avroDoc, err = BuildAvroSchema(fmt.Sprintf("schema_%s", hash), fields)
+	if err != nil {
+		return "", "", err
+	}
+	jsonDoc, err = BuildJSONSchema(fields)
+	if err != nil {
+		return "", "", err
+	}
+	return avroDoc, jsonDoc, nil
+}
+
+func fieldsFromPlatform(detectedFields []map[string]interface{}) []Field {
+	fields := make([]Field, 0, len(detectedFields))
+	for _, raw := range detectedFields {
+		field := Field{}
+		if name, ok := raw["name"].(string); ok {
+			field.Name = name
+		}
+		if typ, ok := raw["type"].(string); ok {
+			field.Type = typ
+		}
+		if required, ok := raw["required"].(bool); ok {
+			field.Required = required
+		}
+		field.SampleValue = raw["sample_value"]
+		fields = append(fields, field)
+	}
+	return fields
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Record is a confirmed or pending schema as known to a Registry.
+type Record struct {
+	Hash       string
+	TenantID   string
+	Status     string // "confirmed", "pending", or registry-specific
+	Version    int    // 1 on first registration, incremented by Evolve; zero from ConfluentRegistry.Register/Evolve, whose response carries only the schema ID
+	Fields     []Field
+	AvroSchema string
+	JSONSchema string
+	// SchemaID is the backend's own numeric schema identifier, set only by
+	// ConfluentRegistry (the ID Confluent Schema Registry assigns on
+	// registration, used in its magic-byte wire-format header). Zero for
+	// every other backend.
+	SchemaID int32
+}
+
+// RegisterRequest describes a newly observed schema awaiting confirmation.
+type RegisterRequest struct {
+	TenantID   string
+	SampleData map[string]interface{}
+	Fields     []Field
+}
+
+// Registry is the pluggable backend behind schema detection, fingerprinting,
+// lookup, and registration. Implementations: PlatformRegistry (the
+// proprietary platform HTTP API), ConfluentRegistry (wire-compatible with
+// Confluent Schema Registry), and FileRegistry (local filesystem, for dev).
+type Registry interface {
+	// Detect traverses data and returns its detected Fields.
+	Detect(data map[string]interface{}) []Field
+	// Fingerprint returns a stable hash identifying the shape described by
+	// fields, independent of field order.
+	Fingerprint(fields []Field) string
+	// Lookup returns the Record for hash/tenantID, or nil if it doesn't
+	// exist yet. ctx bounds any network call the backend makes.
+	Lookup(ctx context.Context, hash, tenantID string) (*Record, error)
+	// Register creates a new pending (or immediately confirmed, depending
+	// on the backend) Record for req. ctx bounds any network call the
+	// backend makes.
+	Register(ctx context.Context, req RegisterRequest) (*Record, error)
+	// Evolve updates an existing Record's fields under the same logical
+	// schema (e.g. after a compatible schema change), reusing its Kafka
+	// topic and bronze file and incrementing Version, returning the
+	// updated Record. ctx bounds any network call the backend makes.
+	Evolve(ctx context.Context, hash, tenantID string, fields []Field) (*Record, error)
+	// ConfirmedForTenant lists every confirmed Record for tenantID, across
+	// all hashes. The compatibility subsystem diffs a newly observed field
+	// set against these to decide if it's really a new schema or a
+	// compatible evolution of one of them. ctx bounds any network call the
+	// backend makes.
+	ConfirmedForTenant(ctx context.Context, tenantID string) ([]Record, error)
+	// Versions returns the version history of the logical schema
+	// identified by hash, oldest first. ctx bounds any network call the
+	// backend makes.
+	Versions(ctx context.Context, hash, tenantID string) ([]Record, error)
+}
+
+// Fingerprint hashes the sorted "name:type" pairs of fields into a short,
+// stable hex string. This is shared by every Registry implementation so two
+// backends never disagree about the hash for the same shape.
+func Fingerprint(fields []Field) string {
+	pairs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", f.Name, f.Type))
+	}
+	sort.Strings(pairs)
+
+	h := sha256.New()
+	h.Write([]byte(strings.Join(pairs, "|")))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
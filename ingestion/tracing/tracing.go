@@ -0,0 +1,59 @@
+// Package tracing wires up OpenTelemetry trace export for the ingestion
+// service and exposes the process-wide Tracer every instrumented package
+// starts spans from.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"ai-data-lake/ingestion/config"
+)
+
+// Tracer is the ingestion service's package-level tracer, set up by Init.
+// Every instrumented package starts its spans from this.
+var Tracer trace.Tracer = otel.Tracer("ai-data-lake/ingestion")
+
+// Init configures the global TracerProvider and W3C trace-context
+// propagator from cfg. When cfg.Enabled is false it installs no provider,
+// leaving otel's global no-op TracerProvider in place, so Tracer.Start
+// calls are cheap but produce no spans. The returned shutdown
+// func flushes and closes the exporter; callers should defer it (or call
+// it during graceful shutdown) so buffered spans aren't lost.
+func Init(ctx context.Context, cfg *config.TracingConfig) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint), otlptracehttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	Tracer = provider.Tracer("ai-data-lake/ingestion")
+
+	return provider.Shutdown, nil
+}